@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+// check grades the schedule of a scheduling file, e.g. a student's, against a reference policy's, reporting
+// the first invariant it breaks, where it first diverges, how its metrics differ and its score to w, and
+// failing if the score is below -pass.
+func check(args []string, w io.Writer) error {
+	var (
+		flags  = flag.NewFlagSet("check", flag.ExitOnError)
+		policy = flags.String("policy", "fcfs", "reference policy to check the schedule against")
+		input  = flags.String("input", "csv", "format of the scheduling file: csv, json, procstat or perf")
+		format = flags.String("format", "text", "format of the report: text or json")
+		pass   = flags.Float64("pass", 100, "score, out of 100, below which the check fails")
+		p      = scheduler.DefaultParams()
+	)
+	p.Flags(flags)
+	_ = flags.Parse(args)
+	if err := p.Parse(); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("%w: must give a scheduling file and a schedule of it to check", scheduler.ErrInvalidArgs)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown report format %q, want text or json", scheduler.ErrInvalidArgs, *format)
+	}
+	pol, err := scheduler.LookupPolicy(*policy)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	defer f.Close()
+	processes, err := scheduler.Load(f, *input, p)
+	if err != nil {
+		return err
+	}
+	schedule, err := os.Open(flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("%v: error opening schedule", err)
+	}
+	defer schedule.Close()
+	report, err := pol.Check(processes, schedule, p)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		report.Render(w)
+	}
+	if report.Score < *pass {
+		return fmt.Errorf("score of %.1f is below the pass mark of %.1f", report.Score, *pass)
+	}
+
+	return nil
+}
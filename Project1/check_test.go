@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+func Test_check(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	var (
+		workload = write("workload.csv", "1,5,0,2\n2,3,1,1\n3,1,2,4\n")
+		fcfs     = write("fcfs.csv", "pid,start,stop\n1,0,5\n2,5,8\n3,8,9\n")
+		sjf      = write("sjf.csv", "pid,start,stop\n1,0,5\n3,5,6\n2,6,9\n")
+	)
+	tests := []struct {
+		name     string
+		args     []string
+		wantOut  string
+		wantFail bool
+		wantErr  error
+	}{
+		{name: "pass", args: []string{workload, fcfs}, wantOut: "Score: 100.0%"},
+		{name: "fail", args: []string{workload, sjf}, wantOut: "First divergence: CPU 0 at 5", wantFail: true},
+		{name: "pass mark", args: []string{"-pass", "30", workload, sjf}, wantOut: "Score: 33.3%"},
+		{name: "another policy", args: []string{"-policy", "sjf", workload, sjf}, wantOut: "Score: 100.0%"},
+		{name: "json", args: []string{"-format", "json", workload, fcfs}, wantOut: `"score": 100`},
+		{name: "no schedule", args: []string{workload}, wantErr: scheduler.ErrInvalidArgs},
+		{name: "unknown format", args: []string{"-format", "html", workload, fcfs}, wantErr: scheduler.ErrInvalidArgs},
+		{name: "unknown policy", args: []string{"-policy", "lottery", workload, fcfs}, wantErr: scheduler.ErrInvalidArgs},
+		{name: "bad schedule", args: []string{workload, workload}, wantErr: scheduler.ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := check(tt.args, &out)
+			if tt.wantErr != nil || !tt.wantFail {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("check() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err == nil {
+				t.Fatal("check() error = nil, want the check to fail")
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("check() wrote %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+#!/usr/bin/env python3
+"""An example plugin policy: shortest remaining time first, re-decided after each quantum.
+
+Run it with: go run ./Project1 -plugin "python3 Project1/example_plugin.py" Project1/scheduler/testdata/contention/processes.csv
+
+The simulator writes a line of JSON to stdin for each message, and the plugin answers each "decide" message
+with a line of JSON naming the ready process to run and for how long.
+"""
+import json
+import sys
+
+QUANTUM = 2
+
+for line in sys.stdin:
+    msg = json.loads(line)
+    if msg["type"] == "end":
+        break
+    if msg["type"] != "decide":
+        continue
+    # Ready processes are listed in the order they became ready, so min keeps the first of equal processes
+    shortest = min(msg["ready"], key=lambda p: p["remaining"])
+    print(json.dumps({"pid": shortest["pid"], "slice": QUANTUM}), flush=True)
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+// live runs a live simulation of one policy, taking processes as CSV records on stdin, and on a socket with
+// -listen, until stdin ends and the processes complete, and then reports its schedule.
+func live(args []string) error {
+	var (
+		flags  = flag.NewFlagSet("live", flag.ExitOnError)
+		policy = flags.String("policy", "rr", "policy to run live: sjf, priority, rr, wrr, vrr, arr, fair or dvfs")
+		speed  = flags.Float64("speed", 1, "times faster than real time the clock runs (0 runs as fast as it can)")
+		listen = flags.String("listen", "", "also take processes on a socket, e.g. unix:/tmp/scheduler.sock or tcp:localhost:9000")
+		format = flags.String("format", "text", "format of the report: text, markdown or latex")
+		p      = scheduler.DefaultParams()
+	)
+	// Unitless times tick once a second, so the simulation can be watched
+	_ = p.Set("tick", "1s")
+	p.Flags(flags)
+	flags.Lookup("tick").Usage = "real time one tick of unitless times lasts at -speed 1"
+	_ = flags.Parse(args)
+
+	s, err := scheduler.NewLiveSession(*policy, p, *speed)
+	if err != nil {
+		return err
+	}
+	if *listen != "" {
+		l, err := listenOn(*listen)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		go acceptSubmissions(l, s)
+	}
+	go func() {
+		submitLines(os.Stdin, os.Stderr, s)
+		s.Close()
+	}()
+
+	res := s.Run(os.Stdout)
+	_, _ = fmt.Println()
+	return res.RenderFormat(os.Stdout, s.Title(), *format)
+}
+
+// listenOn listens on a socket address, "unix:<path>" or "tcp:<host:port>", where the network defaults to TCP.
+func listenOn(addr string) (net.Listener, error) {
+	network, address, ok := strings.Cut(addr, ":")
+	if !ok || network != "unix" && network != "tcp" {
+		network, address = "tcp", addr
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", scheduler.ErrInvalidArgs, err)
+	}
+
+	return l, nil
+}
+
+// acceptSubmissions takes processes from each connection to l until it's closed.
+func acceptSubmissions(l net.Listener, s *scheduler.LiveSession) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			submitLines(conn, conn, s)
+		}()
+	}
+}
+
+// submitLines submits each non-blank line read from r to the session as a CSV record, answering records it
+// rejects with the error on w.
+func submitLines(r io.Reader, w io.Writer, s *scheduler.LiveSession) {
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		if strings.TrimSpace(lines.Text()) == "" {
+			continue
+		}
+		if err := s.Submit(lines.Text()); err != nil {
+			_, _ = fmt.Fprintln(w, err)
+		}
+	}
+	if err := lines.Err(); err != nil {
+		log.Print(err)
+	}
+}
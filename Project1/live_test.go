@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+func Test_listenOn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		addr        string
+		wantNetwork string
+		wantErr     error
+	}{
+		{name: "unix", addr: "unix:" + filepath.Join(t.TempDir(), "scheduler.sock"), wantNetwork: "unix"},
+		{name: "tcp", addr: "tcp:localhost:0", wantNetwork: "tcp"},
+		{name: "tcp by default", addr: "localhost:0", wantNetwork: "tcp"},
+		{name: "bad address", addr: "tcp:nowhere", wantErr: scheduler.ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l, err := listenOn(tt.addr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("listenOn() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer l.Close()
+			if got := l.Addr().Network(); got != tt.wantNetwork {
+				t.Errorf("listenOn() network = %s, want %s", got, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func Test_acceptSubmissions(t *testing.T) {
+	t.Parallel()
+	s, err := scheduler.NewLiveSession("rr", scheduler.DefaultParams(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := listenOn("tcp:localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go acceptSubmissions(l, s)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = conn.Write([]byte("1,3,0\n\n2,x,0\n"))
+	reply := make([]byte, 256)
+	n, err := conn.Read(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(reply[:n]), "record 2: burst") {
+		t.Errorf("reply = %q, want the rejected record's error", reply[:n])
+	}
+	_ = conn.Close()
+
+	// The socket's valid record runs alongside one from stdin
+	var stdin bytes.Buffer
+	submitLines(strings.NewReader("2,2,0\n"), &stdin, s)
+	s.Close()
+	res := s.Run(&bytes.Buffer{})
+	if len(res.Stats) != 2 || stdin.Len() > 0 {
+		t.Errorf("Run() timing = %+v with errors %q, want processes 1 and 2", res.Stats, stdin.String())
+	}
+}
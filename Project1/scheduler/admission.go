@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// memoryConfig is the main memory processes are admitted into, allocated to them in variable partitions.
+type memoryConfig struct {
+	// capacity is the total memory; zero means memory is unlimited and processes are admitted as they arrive.
+	capacity int64
+	// fit chooses the hole each process is allocated from: "first" or "best".
+	fit string
+}
+
+// fits lists the ways of choosing the hole a process is allocated from.
+var fits = map[string]func(holes []partition, size int64) int{
+	// first-fit takes the first hole that is big enough.
+	"first": func(holes []partition, size int64) int {
+		for i := range holes {
+			if holes[i].size >= size {
+				return i
+			}
+		}
+		return -1
+	},
+	// best-fit takes the smallest hole that is big enough.
+	"best": func(holes []partition, size int64) int {
+		best := -1
+		for i := range holes {
+			if holes[i].size >= size && (best < 0 || holes[i].size < holes[best].size) {
+				best = i
+			}
+		}
+		return best
+	},
+}
+
+// validate returns an error if any of the processes can never be admitted.
+func (m memoryConfig) validate(processes []Process) error {
+	if m.capacity < 0 {
+		return fmt.Errorf("%w: memory capacity must not be negative, got %d", ErrInvalidArgs, m.capacity)
+	}
+	if _, ok := fits[m.fit]; !ok {
+		return fmt.Errorf("%w: unknown memory fit %q", ErrInvalidArgs, m.fit)
+	}
+	if m.capacity == 0 {
+		return nil
+	}
+	for _, p := range processes {
+		if p.Memory > m.capacity {
+			return fmt.Errorf("%w: process %d needs %d memory, more than the capacity of %d",
+				ErrInvalidArgs, p.ProcessID, p.Memory, m.capacity)
+		}
+	}
+
+	return nil
+}
+
+// partition is a contiguous range of memory.
+type partition struct {
+	base int64
+	size int64
+}
+
+// memoryPool allocates memory to processes in variable partitions.
+type memoryPool struct {
+	fit string
+	// holes are the free partitions of memory, in address order.
+	holes []partition
+	// allocated is the partition of each process holding memory.
+	allocated map[int]partition
+}
+
+func newMemoryPool(memory memoryConfig) memoryPool {
+	m := memoryPool{fit: memory.fit, allocated: make(map[int]partition)}
+	if memory.capacity > 0 {
+		m.holes = []partition{{size: memory.capacity}}
+	}
+	return m
+}
+
+// reserve allocates size memory to process i from the hole the fit chooses, reporting whether there was a hole
+// big enough.
+func (m *memoryPool) reserve(i int, size int64) bool {
+	h := fits[m.fit](m.holes, size)
+	if h < 0 {
+		return false
+	}
+	m.allocated[i] = partition{base: m.holes[h].base, size: size}
+	m.holes[h].base += size
+	m.holes[h].size -= size
+	if m.holes[h].size == 0 {
+		m.holes = append(m.holes[:h], m.holes[h+1:]...)
+	}
+
+	return true
+}
+
+// release frees the memory of process i, if it holds any, merged with any neighbouring holes.
+func (m *memoryPool) release(i int) {
+	freed, ok := m.allocated[i]
+	if !ok {
+		return
+	}
+	delete(m.allocated, i)
+
+	m.holes = append(m.holes, freed)
+	sort.Slice(m.holes, func(x, y int) bool { return m.holes[x].base < m.holes[y].base })
+	merged := m.holes[:1]
+	for _, h := range m.holes[1:] {
+		last := &merged[len(merged)-1]
+		if last.base+last.size == h.base {
+			last.size += h.size
+			continue
+		}
+		merged = append(merged, h)
+	}
+	m.holes = merged
+}
+
+// admission is the long-term scheduler: it holds arrived processes in a job queue until the processes they
+// depend on have completed and there is memory for them, then admits them to the ready queue.
+// Processes are admitted in arrival order, skipping any that can't be admitted yet.
+type admission struct {
+	processes []Process
+	memory    memoryConfig
+	// dependents lists the positions of the processes that depend on each process.
+	dependents [][]int
+	// unmet counts the dependencies of each process that haven't completed yet.
+	unmet []int
+	// rank is the position of each process in arrival order.
+	rank []int
+	// pending processes haven't arrived yet, in arrival order.
+	pending []int
+	// arrived is whether each process has arrived.
+	arrived []bool
+	// blocked counts the arrived processes still waiting for their dependencies.
+	blocked int
+	// jobQueue holds arrived processes whose dependencies have completed, waiting for memory, in arrival order.
+	jobQueue []int
+	// scanned is how many processes at the front of the job queue didn't fit since a process last completed.
+	scanned int
+	// early holds processes admitted before the last completion that admit hasn't returned yet.
+	early []int
+	memoryPool
+	// admitted is when each process was admitted to the ready queue.
+	admitted []int64
+	// changed is when a process last completed.
+	changed int64
+}
+
+func newAdmission(processes []Process, memory memoryConfig) *admission {
+	a := &admission{
+		processes:  processes,
+		memory:     memory,
+		dependents: make([][]int, len(processes)),
+		unmet:      make([]int, len(processes)),
+		rank:       make([]int, len(processes)),
+		pending:    arrivalOrder(processes),
+		arrived:    make([]bool, len(processes)),
+		memoryPool: newMemoryPool(memory),
+		admitted:   make([]int64, len(processes)),
+	}
+	for r, i := range a.pending {
+		a.rank[i] = r
+	}
+	index := processIndex(processes)
+	for i, p := range processes {
+		for _, dep := range p.DependsOn {
+			a.dependents[index[dep]] = append(a.dependents[index[dep]], i)
+			a.unmet[i]++
+		}
+	}
+
+	return a
+}
+
+// admit moves the processes that have arrived by now into the job queue, and returns those of them
+// admitted to the ready queue.
+// Only completing processes free memory and satisfy dependencies, so a process that can be admitted now could
+// be since it arrived or since a process last completed, whichever is later, and is admitted at that time.
+func (a *admission) admit(now int64) []int {
+	admitted := append(a.early, a.admitArrived(func(arrival int64) bool { return arrival <= now })...)
+	a.early = nil
+
+	return admitted
+}
+
+// admitArrived moves the processes that have arrived into the job queue, and admits those it can.
+func (a *admission) admitArrived(arrived func(arrival int64) bool) []int {
+	for len(a.pending) > 0 && arrived(a.processes[a.pending[0]].ArrivalTime) {
+		i := a.pending[0]
+		a.pending = a.pending[1:]
+		a.arrived[i] = true
+		if a.unmet[i] > 0 {
+			a.blocked++
+			continue
+		}
+		a.jobQueue = append(a.jobQueue, i)
+	}
+
+	// Memory is only freed when a process completes, so only the processes added since then can fit.
+	var (
+		admitted = make([]int, 0)
+		waiting  = a.jobQueue[:a.scanned]
+	)
+	for _, i := range a.jobQueue[a.scanned:] {
+		if !a.allocate(i) {
+			waiting = append(waiting, i)
+			continue
+		}
+		a.admitted[i] = maxi(a.processes[i].ArrivalTime, a.changed)
+		admitted = append(admitted, i)
+	}
+	a.jobQueue = waiting
+	a.scanned = len(waiting)
+
+	return admitted
+}
+
+// allocate reserves memory for a process, reporting whether there was a hole big enough.
+func (a *admission) allocate(i int) bool {
+	size := a.processes[i].Memory
+	if a.memory.capacity == 0 || size == 0 {
+		return true
+	}
+	return a.reserve(i, size)
+}
+
+// complete records that a process completes now, satisfying the processes that depend on it and freeing its
+// memory, merged with any neighbouring holes.
+// Processes that arrived before now are admitted first, as they could only have been admitted before it.
+func (a *admission) complete(i int, now int64) {
+	a.early = append(a.early, a.admitArrived(func(arrival int64) bool { return arrival < now })...)
+	a.changed = now
+	a.scanned = 0
+	for _, d := range a.dependents[i] {
+		if a.unmet[d]--; a.unmet[d] == 0 && a.arrived[d] {
+			// Keep the job queue in arrival order
+			a.blocked--
+			at := sort.Search(len(a.jobQueue), func(q int) bool { return a.rank[a.jobQueue[q]] > a.rank[d] })
+			a.jobQueue = append(a.jobQueue, 0)
+			copy(a.jobQueue[at+1:], a.jobQueue[at:])
+			a.jobQueue[at] = d
+		}
+	}
+
+	a.release(i)
+}
+
+// nextArrival returns when the next process arrives. While nothing is ready or running, only an arrival can
+// make a process ready, so the clock can jump straight to it.
+func (a *admission) nextArrival() int64 {
+	return a.processes[a.pending[0]].ArrivalTime
+}
+
+// arriving reports whether any process is yet to arrive.
+func (a *admission) arriving() bool {
+	return len(a.pending) > 0
+}
+
+// done reports whether every process has been admitted.
+func (a *admission) done() bool {
+	return len(a.pending) == 0 && a.blocked == 0 && len(a.jobQueue) == 0 && len(a.early) == 0
+}
+
+// process returns the process at position i.
+func (a *admission) process(i int) *Process {
+	return &a.processes[i]
+}
+
+// delay returns how long a process waited in the job queue before it was admitted.
+func (a *admission) delay(i int) int64 {
+	return a.admitted[i] - a.processes[i].ArrivalTime
+}
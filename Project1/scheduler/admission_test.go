@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_admission(t *testing.T) {
+	t.Parallel()
+	// Holes after the first three are admitted and the second completes: [30, 50) and [90, 100).
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, Memory: 30},
+		{ProcessID: 2, BurstDuration: 1, Memory: 20},
+		{ProcessID: 3, BurstDuration: 1, Memory: 40},
+		{ProcessID: 4, ArrivalTime: 2, BurstDuration: 1, Memory: 10},
+		{ProcessID: 5, ArrivalTime: 2, BurstDuration: 1, Memory: 30},
+	}
+	tests := []struct {
+		name      string
+		fit       string
+		wantBase  int64
+		wantHoles []partition
+	}{
+		{
+			name:      "first fit",
+			fit:       "first",
+			wantBase:  30,
+			wantHoles: []partition{{base: 40, size: 10}, {base: 90, size: 10}},
+		},
+		{
+			name:      "best fit",
+			fit:       "best",
+			wantBase:  90,
+			wantHoles: []partition{{base: 30, size: 20}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := newAdmission(processes, memoryConfig{capacity: 100, fit: tt.fit})
+			if got := a.admit(0); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+				t.Fatalf("admit(0) = %v, want [0 1 2]", got)
+			}
+			a.complete(1, 1)
+
+			// Process 5 doesn't fit until process 1 frees its memory, but process 4 can skip ahead of it.
+			if got := a.admit(2); !reflect.DeepEqual(got, []int{3}) {
+				t.Fatalf("admit(2) = %v, want [3]", got)
+			}
+			if got := a.allocated[3].base; got != tt.wantBase {
+				t.Errorf("process 4 allocated at %d, want %d", got, tt.wantBase)
+			}
+			if !reflect.DeepEqual(a.holes, tt.wantHoles) {
+				t.Errorf("holes = %v, want %v", a.holes, tt.wantHoles)
+			}
+
+			a.complete(0, 5)
+			if got := a.admit(6); !reflect.DeepEqual(got, []int{4}) {
+				t.Fatalf("admit(6) = %v, want [4]", got)
+			}
+			if got := a.delay(4); got != 3 {
+				t.Errorf("delay() = %d, want 3", got)
+			}
+			if !a.done() {
+				t.Error("done() = false, want true")
+			}
+		})
+	}
+}
+
+func Test_memoryConfig_validate(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 1, Memory: 50}}
+	tests := []struct {
+		name    string
+		memory  memoryConfig
+		wantErr error
+	}{
+		{name: "unlimited", memory: memoryConfig{fit: "first"}},
+		{name: "fits", memory: memoryConfig{capacity: 50, fit: "best"}},
+		{name: "too big", memory: memoryConfig{capacity: 49, fit: "first"}, wantErr: ErrInvalidArgs},
+		{name: "unknown fit", memory: memoryConfig{capacity: 50, fit: "worst"}, wantErr: ErrInvalidArgs},
+		{name: "negative capacity", memory: memoryConfig{capacity: -1, fit: "first"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.memory.validate(processes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_admission_dependencies(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1, DependsOn: []int64{1}},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1},
+	}
+	a := newAdmission(processes, memoryConfig{fit: "first"})
+	if got := a.admit(0); !reflect.DeepEqual(got, []int{0}) {
+		t.Fatalf("admit(0) = %v, want [0]", got)
+	}
+
+	// Process 3 arrives while process 1 runs and is admitted at once, but process 2 waits for process 1.
+	a.complete(0, 4)
+	if got := a.admit(4); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Fatalf("admit(4) = %v, want [2 1]", got)
+	}
+	if got := a.delay(2); got != 0 {
+		t.Errorf("delay() of process 3 = %d, want 0", got)
+	}
+	if got := a.delay(1); got != 3 {
+		t.Errorf("delay() of process 2 = %d, want 3", got)
+	}
+}
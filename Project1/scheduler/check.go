@@ -0,0 +1,416 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	// CheckReport is how a schedule of processes, e.g. a student's, compares to the schedule of the same
+	// processes by a reference policy.
+	CheckReport struct {
+		Policy string
+		// Invariant is the first scheduling invariant the schedule breaks; nil if it keeps them, or if it's a
+		// table of timing, which has no slices to check.
+		Invariant error
+		// Divergence is where the schedule first differs from the reference; empty if they're the same.
+		Divergence string
+		// Metrics compares the averages of the schedule to those of the reference.
+		Metrics []MetricDiff
+		// Processes compares the timing of each process whose timing differs from the reference.
+		Processes []ProcessDiff
+		// Matched is how many processes of Total have the same timing as in the reference.
+		Matched, Total int
+		// Score is the percentage of processes timed as in the reference, or zero if an invariant is broken.
+		Score float64
+		title string
+		scale timeScale
+	}
+	// MetricDiff is a metric of a schedule and of the reference schedule.
+	MetricDiff struct {
+		Name      string
+		Got, Want float64
+		// key names the metric in JSON.
+		key string
+	}
+	// ProcessDiff is the timing of a process in a schedule and in the reference schedule.
+	ProcessDiff struct {
+		PID       int64
+		Got, Want ProcessStats
+	}
+)
+
+// Check compares a schedule of processes read from r to the policy's schedule of them with the parameters in p.
+// The schedule is a CSV file with a header, either of its slices, with the columns pid, start and stop, and
+// optionally cpu, level (the frequency state) and io (true for the times a process is blocked on I/O, rather
+// than running), or a table of each process's timing, with the columns id and exit, and optionally turnaround
+// and wait, which default to the time from arrival to exit, and that less the burst. A schedule of slices is
+// checked against the scheduling invariants, and either is compared to the reference, slice by slice or process
+// by process, for where they first diverge, how their averages differ, and which processes are timed differently.
+func (pol Policy) Check(processes []Process, r io.Reader, p Params) (CheckReport, error) {
+	want, err := pol.Schedule(processes, p)
+	if err != nil {
+		return CheckReport{}, err
+	}
+	got, slices, err := readSchedule(r, processes, want, p)
+	if err != nil {
+		return CheckReport{}, err
+	}
+
+	report := CheckReport{Policy: pol.name, Total: len(processes), title: pol.title, scale: p.time}
+	if slices {
+		report.Invariant = verifySchedule(processes, got, pol.workConserving)
+		report.Divergence = ganttDivergence(got, want, pol.title, p.time)
+	}
+
+	gotStats := make(map[int64]ProcessStats, len(got.Stats))
+	for _, s := range got.Stats {
+		gotStats[s.ProcessID] = s
+	}
+	var (
+		firstAt    int64 = -1
+		divergence string
+	)
+	for _, w := range want.Stats {
+		g, ok := gotStats[w.ProcessID]
+		if ok && g.Wait == w.Wait && g.Turnaround == w.Turnaround && g.Exit == w.Exit {
+			report.Matched++
+			continue
+		}
+		report.Processes = append(report.Processes, ProcessDiff{PID: w.ProcessID, Got: g, Want: w})
+		at := w.Exit
+		if ok {
+			at = mini(at, g.Exit)
+		}
+		if firstAt < 0 || at < firstAt {
+			firstAt = at
+			if ok {
+				divergence = fmt.Sprintf("process %d exits at %s, but %s has it exit at %s",
+					w.ProcessID, p.time.format(g.Exit), pol.title, p.time.format(w.Exit))
+			} else {
+				divergence = fmt.Sprintf("process %d never runs, but %s has it exit at %s", w.ProcessID, pol.title, p.time.format(w.Exit))
+			}
+		}
+	}
+	if report.Divergence == "" {
+		report.Divergence = divergence
+	}
+
+	gotMetrics, wantMetrics := checkMetrics(got, p.time), checkMetrics(want, p.time)
+	for i, metric := range [][2]string{
+		{"Average wait", "averageWait"},
+		{"Average turnaround", "averageTurnaround"},
+		{"Throughput", "throughput"},
+		{"Makespan", "makespan"},
+	} {
+		report.Metrics = append(report.Metrics, MetricDiff{Name: metric[0], Got: gotMetrics[i], Want: wantMetrics[i], key: metric[1]})
+	}
+	if report.Invariant == nil && report.Total > 0 {
+		report.Score = 100 * float64(report.Matched) / float64(report.Total)
+	}
+
+	return report, nil
+}
+
+// checkMetrics returns the average wait and turnaround, the throughput and the makespan of a schedule, in the
+// unit of its time scale; zero for a schedule of no processes.
+func checkMetrics(r Result, ts timeScale) [4]float64 {
+	if len(r.Stats) == 0 {
+		return [4]float64{}
+	}
+	perUnit := float64(ts.perUnit())
+	wait, turnaround, throughput := r.averages()
+	return [4]float64{wait / perUnit, turnaround / perUnit, throughput * perUnit, float64(r.makespan()) / perUnit}
+}
+
+// readSchedule reads a schedule of processes to check as a Result, given the reference schedule, reporting
+// whether it's a schedule of slices rather than a table of timing.
+func readSchedule(r io.Reader, processes []Process, want Result, p Params) (Result, bool, error) {
+	records := csv.NewReader(r)
+	records.FieldsPerRecord = -1
+	header, err := records.Read()
+	if err != nil {
+		return Result{}, false, fmt.Errorf("%w: reading the header: %v", ErrInvalidSchedule, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	byPID := make(map[int64]Process, len(processes))
+	for _, proc := range processes {
+		byPID[proc.ProcessID] = proc
+	}
+
+	_, slices := columns["start"]
+	required := []string{"id", "exit"}
+	if slices {
+		required = []string{"pid", "start", "stop"}
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return Result{}, false, fmt.Errorf("%w: header: missing column %q, want pid, start and stop, or id and exit",
+				ErrInvalidSchedule, name)
+		}
+	}
+
+	got := Result{Gantt: make([]TimeSlice, 0), MemoryCapacity: want.MemoryCapacity, CPU: want.CPU, Time: p.time}
+	seen := make(map[int64]bool)
+	for record := 1; ; record++ {
+		row, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, false, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		field := func(name string) (string, bool) {
+			i, ok := columns[name]
+			if !ok || i >= len(row) || strings.TrimSpace(row[i]) == "" {
+				return "", false
+			}
+			return strings.TrimSpace(row[i]), true
+		}
+		var failed error
+		integer := func(name string, parse func(string) (int64, error)) int64 {
+			s, ok := field(name)
+			if !ok || failed != nil {
+				return 0
+			}
+			n, err := parse(s)
+			if err != nil {
+				failed = fmt.Errorf("%w: record %d: %s: %v", ErrInvalidSchedule, record, name, err)
+			}
+			return n
+		}
+		parseInt := func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }
+
+		if !slices {
+			id := integer("id", parseInt)
+			proc, ok := byPID[id]
+			switch {
+			case failed != nil:
+				return Result{}, false, failed
+			case !ok:
+				return Result{}, false, fmt.Errorf("%w: record %d: process %d isn't in the workload", ErrInvalidSchedule, record, id)
+			case seen[id]:
+				return Result{}, false, fmt.Errorf("%w: record %d: process %d is timed twice", ErrInvalidSchedule, record, id)
+			}
+			seen[id] = true
+			s := ProcessStats{Process: proc, Exit: integer("exit", p.time.parse)}
+			s.Turnaround = s.Exit - s.ArrivalTime
+			if _, ok := field("turnaround"); ok {
+				s.Turnaround = integer("turnaround", p.time.parse)
+			}
+			s.Wait = s.Turnaround - s.BurstDuration
+			if _, ok := field("wait"); ok {
+				s.Wait = integer("wait", p.time.parse)
+			}
+			if failed != nil {
+				return Result{}, false, failed
+			}
+			got.Stats = append(got.Stats, s)
+			continue
+		}
+
+		slice := TimeSlice{
+			PID:   integer("pid", parseInt),
+			Start: integer("start", p.time.parse),
+			Stop:  integer("stop", p.time.parse),
+			CPU:   int(integer("cpu", parseInt)),
+			Level: int(integer("level", parseInt)),
+		}
+		io := false
+		if s, ok := field("io"); ok {
+			if io, err = strconv.ParseBool(s); err != nil {
+				failed = fmt.Errorf("%w: record %d: io: %v", ErrInvalidSchedule, record, err)
+			}
+		}
+		if failed != nil {
+			return Result{}, false, failed
+		}
+		if io {
+			got.IO = append(got.IO, slice)
+		} else {
+			got.Gantt = append(got.Gantt, slice)
+		}
+	}
+	if slices {
+		got.Stats = scheduleStats(processes, got)
+	}
+
+	return got, slices, nil
+}
+
+// scheduleStats returns the timing of each process that runs in a schedule of slices, in the order of the
+// processes. A process is admitted as it arrives, unless it must wait for memory or the processes it depends on,
+// when it's taken to be admitted as it first runs.
+func scheduleStats(processes []Process, res Result) []ProcessStats {
+	type timing struct {
+		first, exit, ran, io int64
+		runs                 bool
+	}
+	timings := make(map[int64]*timing, len(processes))
+	for _, proc := range processes {
+		timings[proc.ProcessID] = &timing{}
+	}
+	for _, slice := range res.Gantt {
+		if t, ok := timings[slice.PID]; ok {
+			if !t.runs || slice.Start < t.first {
+				t.first = slice.Start
+			}
+			t.runs = true
+			t.exit = maxi(t.exit, slice.Stop)
+			t.ran += slice.Stop - slice.Start
+		}
+	}
+	for _, io := range res.IO {
+		if t, ok := timings[io.PID]; ok {
+			t.io += io.Stop - io.Start
+		}
+	}
+
+	stats := make([]ProcessStats, 0, len(processes))
+	for _, proc := range processes {
+		t := timings[proc.ProcessID]
+		if !t.runs {
+			continue
+		}
+		s := ProcessStats{Process: proc, IO: t.io, Turnaround: t.exit - proc.ArrivalTime, Exit: t.exit}
+		if res.MemoryCapacity != 0 || len(proc.DependsOn) > 0 {
+			s.Admission = maxi(0, t.first-proc.ArrivalTime)
+		}
+		s.Wait = s.Turnaround - t.ran - t.io
+		stats = append(stats, s)
+	}
+
+	return stats
+}
+
+// ganttDivergence returns where a schedule's GANTT chart first differs from the reference's, by the segments
+// of each CPU, where consecutive slices of a process merge; empty if they're the same.
+func ganttDivergence(got, want Result, title string, ts timeScale) string {
+	var (
+		gotRows, wantRows       = ganttRows(got.Gantt), ganttRows(want.Gantt)
+		firstAt           int64 = -1
+		divergence        string
+	)
+	describe := func(g ganttSegment) string {
+		if g.idle {
+			return fmt.Sprintf("is idle from %s to %s", ts.format(g.start), ts.format(g.stop))
+		}
+		return fmt.Sprintf("runs process %d from %s to %s", g.pid, ts.format(g.start), ts.format(g.stop))
+	}
+	for cpu := 0; cpu < maxInt(len(gotRows), len(wantRows)); cpu++ {
+		var gotRow, wantRow []ganttSegment
+		if cpu < len(gotRows) {
+			gotRow = gotRows[cpu]
+		}
+		if cpu < len(wantRows) {
+			wantRow = wantRows[cpu]
+		}
+		for i := 0; i < maxInt(len(gotRow), len(wantRow)); i++ {
+			var (
+				at      int64
+				message string
+			)
+			switch {
+			case i >= len(gotRow):
+				at = wantRow[i].start
+				message = fmt.Sprintf("the schedule ends, but %s %s", title, describe(wantRow[i]))
+			case i >= len(wantRow):
+				at = gotRow[i].start
+				message = fmt.Sprintf("the schedule %s, but %s has ended", describe(gotRow[i]), title)
+			case gotRow[i].pid != wantRow[i].pid || gotRow[i].idle != wantRow[i].idle ||
+				gotRow[i].start != wantRow[i].start || gotRow[i].stop != wantRow[i].stop:
+				at = mini(gotRow[i].start, wantRow[i].start)
+				message = fmt.Sprintf("the schedule %s, but %s %s", describe(gotRow[i]), title, describe(wantRow[i]))
+			default:
+				continue
+			}
+			if firstAt < 0 || at < firstAt {
+				firstAt = at
+				divergence = fmt.Sprintf("CPU %d at %s: %s", cpu, ts.format(at), message)
+			}
+			break
+		}
+	}
+
+	return divergence
+}
+
+// Render outputs the report: whether the schedule keeps the invariants, where it first diverges from the
+// reference, its metrics against the reference's, the processes timed differently and the score.
+func (c CheckReport) Render(w io.Writer) {
+	ts := c.scale
+	_, _ = fmt.Fprintf(w, "Checked against %s\n", c.title)
+	if c.Invariant != nil {
+		_, _ = fmt.Fprintf(w, "Invariant broken: %v\n", c.Invariant)
+	}
+	if c.Divergence != "" {
+		_, _ = fmt.Fprintf(w, "First divergence: %s\n", c.Divergence)
+	} else {
+		_, _ = fmt.Fprintln(w, "The schedule matches the reference")
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Metric", "Schedule", "Reference", "Difference"})
+	for _, m := range c.Metrics {
+		table.Append([]string{m.Name, fmt.Sprintf("%.2f", m.Got), fmt.Sprintf("%.2f", m.Want), fmt.Sprintf("%.2f", m.Got-m.Want)})
+	}
+	table.Render()
+
+	if len(c.Processes) > 0 {
+		table = tablewriter.NewWriter(w)
+		table.SetHeader([]string{"ID", ts.label("Wait"), ts.label("Want wait"), ts.label("Turnaround"), ts.label("Want turnaround"), ts.label("Exit"), ts.label("Want exit")})
+		for _, d := range c.Processes {
+			table.Append([]string{
+				fmt.Sprint(d.PID),
+				ts.format(d.Got.Wait), ts.format(d.Want.Wait),
+				ts.format(d.Got.Turnaround), ts.format(d.Want.Turnaround),
+				ts.format(d.Got.Exit), ts.format(d.Want.Exit),
+			})
+		}
+		table.Render()
+	}
+	_, _ = fmt.Fprintf(w, "Score: %.1f%% (%d of %d processes timed as in the reference)\n", c.Score, c.Matched, c.Total)
+}
+
+// MarshalJSON encodes the report with times on its time scale, and the invariant broken as its message.
+func (c CheckReport) MarshalJSON() ([]byte, error) {
+	type diffJSON struct {
+		PID  int64       `json:"pid"`
+		Got  processJSON `json:"got"`
+		Want processJSON `json:"want"`
+	}
+	var invariant string
+	if c.Invariant != nil {
+		invariant = c.Invariant.Error()
+	}
+	metrics := make(map[string]map[string]float64, len(c.Metrics))
+	for _, m := range c.Metrics {
+		metrics[m.key] = map[string]float64{"got": m.Got, "want": m.Want}
+	}
+	processes := make([]diffJSON, len(c.Processes))
+	for i, d := range c.Processes {
+		processes[i] = diffJSON{PID: d.PID, Got: c.scale.processJSON(d.Got), Want: c.scale.processJSON(d.Want)}
+	}
+
+	return json.Marshal(struct {
+		Policy     string                        `json:"policy"`
+		Unit       string                        `json:"unit,omitempty"`
+		Invariant  string                        `json:"invariant,omitempty"`
+		Divergence string                        `json:"divergence,omitempty"`
+		Metrics    map[string]map[string]float64 `json:"metrics"`
+		Processes  []diffJSON                    `json:"processes"`
+		Matched    int                           `json:"matched"`
+		Total      int                           `json:"total"`
+		Score      float64                       `json:"score"`
+	}{c.Policy, c.scale.unit, invariant, c.Divergence, metrics, processes, c.Matched, c.Total, c.Score})
+}
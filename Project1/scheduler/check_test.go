@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// scheduleCSV writes the slices of a schedule as a CSV file to check.
+func scheduleCSV(res Result) string {
+	var b strings.Builder
+	b.WriteString("pid,start,stop,cpu,level,io\n")
+	for _, slice := range res.Gantt {
+		if !slice.Idle {
+			fmt.Fprintf(&b, "%d,%d,%d,%d,%d,false\n", slice.PID, slice.Start, slice.Stop, slice.CPU, slice.Level)
+		}
+	}
+	for _, io := range res.IO {
+		fmt.Fprintf(&b, "%d,%d,%d,%d,0,true\n", io.PID, io.Start, io.Stop, io.CPU)
+	}
+	return b.String()
+}
+
+func TestPolicy_Check_reference(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1, Priority: 3},
+		{ProcessID: 4, ArrivalTime: 9, BurstDuration: 2, Priority: 1},
+	}
+	p := DefaultParams()
+	for name, value := range map[string]string{"io-every": "2", "io-time": "1", "cpus": "2"} {
+		if err := p.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, pol := range Policies() {
+		pol := pol
+		t.Run(pol.Name(), func(t *testing.T) {
+			t.Parallel()
+			res, err := pol.Schedule(processes, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := pol.Check(processes, strings.NewReader(scheduleCSV(res)), p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Invariant != nil || report.Divergence != "" || report.Score != 100 {
+				t.Errorf("Check() of the reference schedule = %v, %q, score %v; want no invariant broken, no divergence, score 100",
+					report.Invariant, report.Divergence, report.Score)
+			}
+		})
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1},
+	}
+	tests := []struct {
+		name           string
+		schedule       string
+		wantInvariant  bool
+		wantDivergence string
+		wantScore      float64
+		wantErr        error
+	}{
+		{name: "same slices", schedule: "pid,start,stop\n1,0,5\n2,5,8\n3,8,9\n", wantScore: 100},
+		{name: "same slices split", schedule: "pid,start,stop\n1,0,2\n1,2,5\n2,5,8\n3,8,9\n", wantScore: 100},
+		{
+			name:     "another policy",
+			schedule: "pid,start,stop\n1,0,5\n3,5,6\n2,6,9\n",
+			wantDivergence: "CPU 0 at 5: the schedule runs process 3 from 5 to 6, " +
+				"but First-come, first-serve runs process 2 from 5 to 8",
+			wantScore: 100.0 / 3,
+		},
+		{
+			name:           "ends early",
+			schedule:       "pid,start,stop\n1,0,5\n2,5,8\n",
+			wantInvariant:  true,
+			wantDivergence: "CPU 0 at 8: the schedule ends, but First-come, first-serve runs process 3 from 8 to 9",
+		},
+		{
+			name:           "short burst",
+			schedule:       "pid,start,stop\n1,0,4\n2,5,8\n3,8,9\n",
+			wantInvariant:  true,
+			wantDivergence: "CPU 0 at 0: the schedule runs process 1 from 0 to 4, but First-come, first-serve runs process 1 from 0 to 5",
+		},
+		{name: "same table", schedule: "id,exit,wait\n3,9,6\n1,5,0\n2,8,4\n", wantScore: 100},
+		{
+			name:           "table",
+			schedule:       "id,exit\n1,5\n2,9\n3,6\n",
+			wantDivergence: "process 3 exits at 6, but First-come, first-serve has it exit at 9",
+			wantScore:      100.0 / 3,
+		},
+		{
+			name:           "table missing a process",
+			schedule:       "id,exit\n1,5\n2,8\n",
+			wantDivergence: "process 3 never runs, but First-come, first-serve has it exit at 9",
+			wantScore:      200.0 / 3,
+		},
+		{name: "empty", schedule: "", wantErr: ErrInvalidSchedule},
+		{name: "bad header", schedule: "pid,when\n1,0\n", wantErr: ErrInvalidSchedule},
+		{name: "bad time", schedule: "pid,start,stop\n1,0,five\n", wantErr: ErrInvalidSchedule},
+		{name: "bad io", schedule: "pid,start,stop,io\n1,0,5,maybe\n", wantErr: ErrInvalidSchedule},
+		{name: "unknown process", schedule: "id,exit\n4,5\n", wantErr: ErrInvalidSchedule},
+		{name: "process timed twice", schedule: "id,exit\n1,5\n1,5\n", wantErr: ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pol, err := LookupPolicy("fcfs")
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := pol.Check(processes, strings.NewReader(tt.schedule), DefaultParams())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := report.Invariant != nil; got != tt.wantInvariant {
+				t.Errorf("Check() Invariant = %v, want broken %v", report.Invariant, tt.wantInvariant)
+			}
+			if tt.wantInvariant && !errors.Is(report.Invariant, ErrInvariant) {
+				t.Errorf("Check() Invariant = %v, want %v", report.Invariant, ErrInvariant)
+			}
+			if report.Divergence != tt.wantDivergence {
+				t.Errorf("Check() Divergence = %q, want %q", report.Divergence, tt.wantDivergence)
+			}
+			if report.Score != tt.wantScore {
+				t.Errorf("Check() Score = %v, want %v", report.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestCheckReport(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 3}}
+	pol, err := LookupPolicy("fcfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := pol.Check(processes, strings.NewReader("pid,start,stop\n2,1,4\n1,4,9\n"), DefaultParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	report.Render(&b)
+	for _, want := range []string{
+		"Checked against First-come, first-serve\n",
+		"First divergence: CPU 0 at 0: the schedule is idle from 0 to 1, but First-come, first-serve runs process 1 from 0 to 5\n",
+		"| Average wait       |     2.00 |      2.00 |       0.00 |",
+		"Score: 0.0% (0 of 2 processes timed as in the reference)\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Render() = %q, want it to contain %q", b.String(), want)
+		}
+	}
+
+	var got struct {
+		Policy    string                        `json:"policy"`
+		Metrics   map[string]map[string]float64 `json:"metrics"`
+		Processes []struct {
+			PID int64 `json:"pid"`
+			Got struct {
+				Exit json.Number `json:"exit"`
+			} `json:"got"`
+		} `json:"processes"`
+		Score float64 `json:"score"`
+	}
+	b.Reset()
+	if err := json.NewEncoder(&b).Encode(report); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Policy != "fcfs" || got.Metrics["makespan"]["got"] != 9 || got.Metrics["makespan"]["want"] != 8 ||
+		len(got.Processes) != 2 || got.Processes[0].Got.Exit != "9" || got.Score != 0 {
+		t.Errorf("MarshalJSON() = %s", b.String())
+	}
+}
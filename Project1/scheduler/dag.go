@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dependsColumn sets the processes a process depends on from a list of process IDs such as "3;5".
+func dependsColumn(p *Process, s string, _ timeScale) error {
+	p.DependsOn = nil
+	for _, field := range strings.Split(s, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return err
+		}
+		p.DependsOn = append(p.DependsOn, id)
+	}
+
+	return nil
+}
+
+// processIndex returns the position of each process by its ID.
+func processIndex(processes []Process) map[int64]int {
+	index := make(map[int64]int, len(processes))
+	for i, p := range processes {
+		index[p.ProcessID] = i
+	}
+	return index
+}
+
+// validateDependencies returns an error if a process depends on an unknown process,
+// or if the dependencies form a cycle, including a process depending on itself.
+func validateDependencies(processes []Process) error {
+	index := processIndex(processes)
+	for i, p := range processes {
+		for _, dep := range p.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("%w: record %d: process %d depends on unknown process %d",
+					ErrInvalidProcesses, i+1, p.ProcessID, dep)
+			}
+		}
+	}
+
+	// Search the dependencies depth-first for one that is already on the path to the current process
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = make([]int, len(processes))
+		path  []string
+		visit func(i int) error
+	)
+	visit = func(i int) error {
+		state[i] = visiting
+		path = append(path, fmt.Sprint(processes[i].ProcessID))
+		for _, dep := range processes[i].DependsOn {
+			switch j := index[dep]; state[j] {
+			case visiting:
+				cycle := path
+				for cycle[0] != fmt.Sprint(dep) {
+					cycle = cycle[1:]
+				}
+				return fmt.Errorf("%w: dependency cycle: %s depends on %d",
+					ErrInvalidProcesses, strings.Join(cycle, " depends on "), dep)
+			case unvisited:
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+	for i := range processes {
+		if state[i] == unvisited {
+			if err := visit(i); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// criticalPath returns when the last of the processes would exit with a CPU each, starting every process
+// as soon as it has arrived and the processes it depends on have exited.
+// No schedule can finish sooner, however many CPUs it has.
+func criticalPath(processes []Process) int64 {
+	var (
+		index    = processIndex(processes)
+		exit     = make([]int64, len(processes))
+		finished = make([]bool, len(processes))
+		earliest func(i int) int64
+		last     int64
+	)
+	earliest = func(i int) int64 {
+		if !finished[i] {
+			start := processes[i].ArrivalTime
+			for _, dep := range processes[i].DependsOn {
+				start = maxi(start, earliest(index[dep]))
+			}
+			exit[i], finished[i] = start+processes[i].BurstDuration, true
+		}
+		return exit[i]
+	}
+	for i := range processes {
+		last = maxi(last, earliest(i))
+	}
+
+	return last
+}
+
+// hasDependencies reports whether any scheduled process depends on another.
+func (r Result) hasDependencies() bool {
+	for _, s := range r.Stats {
+		if len(s.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// makespan returns when the last process of a schedule exits.
+func (r Result) makespan() int64 {
+	var last int64
+	for _, s := range r.Stats {
+		last = maxi(last, s.Exit)
+	}
+	return last
+}
+
+// criticalPathSection reports the makespan of a schedule against the critical path of its processes.
+func (r Result) criticalPathSection() reportSection {
+	processes := make([]Process, len(r.Stats))
+	for i := range r.Stats {
+		processes[i] = r.Stats[i].Process
+	}
+	return reportSection{lines: []string{
+		fmt.Sprintf("Makespan: %s, critical path: %s", r.Time.format(r.makespan()), r.Time.format(criticalPath(processes))),
+	}}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_validateDependencies(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantErr   string
+	}{
+		{
+			name: "dag",
+			processes: []Process{
+				{ProcessID: 1},
+				{ProcessID: 2, DependsOn: []int64{1}},
+				{ProcessID: 3, DependsOn: []int64{1, 2}},
+			},
+		},
+		{
+			name:      "unknown process",
+			processes: []Process{{ProcessID: 1, DependsOn: []int64{7}}},
+			wantErr:   "invalid processes: record 1: process 1 depends on unknown process 7",
+		},
+		{
+			name:      "depends on itself",
+			processes: []Process{{ProcessID: 1, DependsOn: []int64{1}}},
+			wantErr:   "invalid processes: dependency cycle: 1 depends on 1",
+		},
+		{
+			name: "cycle",
+			processes: []Process{
+				{ProcessID: 1},
+				{ProcessID: 2, DependsOn: []int64{1, 4}},
+				{ProcessID: 3, DependsOn: []int64{2}},
+				{ProcessID: 4, DependsOn: []int64{3}},
+			},
+			wantErr: "invalid processes: dependency cycle: 2 depends on 4 depends on 3 depends on 2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateDependencies(tt.processes)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateDependencies() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr || !errors.Is(err, ErrInvalidProcesses) {
+				t.Errorf("validateDependencies() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_criticalPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      int64
+	}{
+		{
+			name: "independent",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 4},
+			},
+			want: 7,
+		},
+		{
+			name: "longest chain",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 6},
+				{ProcessID: 3, ArrivalTime: 0, BurstDuration: 1, DependsOn: []int64{1, 2}},
+				{ProcessID: 4, ArrivalTime: 0, BurstDuration: 3, DependsOn: []int64{1}},
+			},
+			want: 7,
+		},
+		{
+			name: "late arrival",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: 2, ArrivalTime: 10, BurstDuration: 2, DependsOn: []int64{1}},
+			},
+			want: 12,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := criticalPath(tt.processes); got != tt.want {
+				t.Errorf("criticalPath() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
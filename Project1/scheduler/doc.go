@@ -0,0 +1,25 @@
+// Package scheduler simulates CPU scheduling policies over a workload of processes.
+//
+// Load a workload, run it through a policy and render the result:
+//
+//	p := scheduler.DefaultParams()
+//	if err := p.Set("quantum", "3"); err != nil {
+//		return err
+//	}
+//	processes, err := scheduler.Load(f, "csv", p)
+//	if err != nil {
+//		return err
+//	}
+//	for _, pol := range scheduler.Policies() {
+//		res, err := pol.Schedule(processes, p)
+//		if err != nil {
+//			return err
+//		}
+//		res.Render(os.Stdout, pol.Title())
+//	}
+//
+// Params are set by the names of the command-line flags of the CLI, which Params.Flags defines on a flag set.
+// A Result holds the GANTT chart and the timing of every process, which Policy.Verify checks against the
+// scheduling invariants. Stream schedules a CSV file too big to load as it's read, and an Experiment runs a
+// workload through a list of policies with their own parameters, read from a YAML or JSON experiment file.
+package scheduler
@@ -0,0 +1,402 @@
+package scheduler
+
+import "container/heap"
+
+// readyQueue holds the processes admitted to run, by their position in the processes.
+type readyQueue interface {
+	push(i int)
+	pop() int
+	len() int
+	// each calls f with each queued process, in no particular order.
+	each(f func(i int))
+}
+
+// fifoQueue is a first-in, first-out ready queue.
+type fifoQueue struct {
+	queue []int
+	head  int
+}
+
+func (q *fifoQueue) push(i int) {
+	q.queue = append(q.queue, i)
+}
+
+func (q *fifoQueue) pop() int {
+	i := q.queue[q.head]
+	q.head++
+	// Reuse the popped space rather than growing the queue forever
+	if q.head > 1024 && q.head > len(q.queue)/2 {
+		q.queue = q.queue[:copy(q.queue, q.queue[q.head:])]
+		q.head = 0
+	}
+	return i
+}
+
+func (q *fifoQueue) len() int {
+	return len(q.queue) - q.head
+}
+
+func (q *fifoQueue) each(f func(i int)) {
+	for _, i := range q.queue[q.head:] {
+		f(i)
+	}
+}
+
+// heapQueue is a ready queue that pops the first process by less, and the first pushed of equal processes.
+type heapQueue struct {
+	items queuedItems
+	seq   int64
+}
+
+func newHeapQueue(less func(a, b int) bool) *heapQueue {
+	return &heapQueue{items: queuedItems{less: less}}
+}
+
+func (q *heapQueue) push(i int) {
+	q.seq++
+	heap.Push(&q.items, queued{i: i, seq: q.seq})
+}
+
+func (q *heapQueue) pop() int {
+	return heap.Pop(&q.items).(queued).i
+}
+
+func (q *heapQueue) len() int {
+	return len(q.items.queue)
+}
+
+func (q *heapQueue) each(f func(i int)) {
+	for _, item := range q.items.queue {
+		f(item.i)
+	}
+}
+
+// queued is a process in a heapQueue, with the order it was pushed in.
+type queued struct {
+	i   int
+	seq int64
+}
+
+// queuedItems implements heap.Interface for heapQueue.
+type queuedItems struct {
+	less  func(a, b int) bool
+	queue []queued
+}
+
+func (h queuedItems) Len() int { return len(h.queue) }
+
+func (h queuedItems) Less(a, b int) bool {
+	x, y := h.queue[a], h.queue[b]
+	if h.less(x.i, y.i) {
+		return true
+	}
+	if h.less(y.i, x.i) {
+		return false
+	}
+	return x.seq < y.seq
+}
+
+func (h queuedItems) Swap(a, b int) { h.queue[a], h.queue[b] = h.queue[b], h.queue[a] }
+
+func (h *queuedItems) Push(x any) { h.queue = append(h.queue, x.(queued)) }
+
+func (h *queuedItems) Pop() any {
+	last := h.queue[len(h.queue)-1]
+	h.queue = h.queue[:len(h.queue)-1]
+	return last
+}
+
+// event is when a CPU finishes running a slice, when the next process arrives, or when the process in slot
+// returns from I/O.
+type event struct {
+	at  int64
+	cpu int
+	// slot is the process returning from I/O.
+	slot int
+}
+
+// The CPU of the events that don't finish a slice.
+const (
+	arrivalEvent  = -1
+	ioReturnEvent = -2
+)
+
+// calendar holds the upcoming events, earliest first, and CPUs before returns from I/O before arrivals at the
+// same time.
+type calendar []event
+
+func (c calendar) Len() int { return len(c) }
+
+func (c calendar) Less(a, b int) bool {
+	if c[a].at != c[b].at {
+		return c[a].at < c[b].at
+	}
+	return uint(c[a].cpu) < uint(c[b].cpu)
+}
+
+func (c calendar) Swap(a, b int) { c[a], c[b] = c[b], c[a] }
+
+func (c *calendar) Push(x any) { *c = append(*c, x.(event)) }
+
+func (c *calendar) Pop() any {
+	last := (*c)[len(*c)-1]
+	*c = (*c)[:len(*c)-1]
+	return last
+}
+
+// arrivals admits processes to the engine, each in a slot, and is told when they complete.
+type arrivals interface {
+	// admit returns the slots of the processes admitted by now.
+	admit(now int64) []int
+	// complete records that the process in slot i completes now.
+	complete(i int, now int64)
+	// arriving reports whether any process is yet to arrive, and nextArrival when the next one does.
+	arriving() bool
+	nextArrival() int64
+	// process returns the process in slot i, and delay how long it waited to be admitted.
+	process(i int) *Process
+	delay(i int) int64
+}
+
+// clock is a source of arrivals that holds the simulation to real time, and takes processes as it runs.
+type clock interface {
+	// wait returns once the clock may advance from now to next, the next event, or earlier with the time a
+	// process arrives. Without a next event (-1) it waits for a process to arrive, or returns -1 once none will.
+	wait(now, next int64) int64
+}
+
+// discipline is how a policy runs the ready processes.
+type discipline struct {
+	// less orders the ready queue, running the first admitted of equal processes first;
+	// nil runs the ready processes first-in, first-out.
+	less func(a, b *Process) bool
+	// run returns how much of the remaining work of a process to run next, measured at full speed, and the
+	// frequency level to run it at, given how many processes are still waiting.
+	run func(p *Process, remaining int64, waiting int) (work int64, level int)
+	// round, if set, is called with the remaining work of the ready processes at the start of each round, once
+	// every process that was ready at the start of the last round has run, so run can adapt to them.
+	round func(remaining []int64)
+	// arrivalsFirst queues processes admitted as others finish their slice ahead of them, rather than behind.
+	arrivalsFirst bool
+	// io blocks processes on I/O as they run.
+	io ioModel
+	// virtual queues processes returning from I/O in an auxiliary queue that runs ahead of the ready queue,
+	// for the rest of the work run gave their slice before they blocked.
+	virtual bool
+	// ties orders processes admitted together, and processes less finds equal.
+	ties tieBreak
+	// queue, if set, makes the ready queue in place of less, given the remaining work of each process and the
+	// time, for a queue that decides what to run as it's popped.
+	queue func(src arrivals, remaining func(i int) int64, now func() int64) readyQueue
+}
+
+// ioModel has processes request I/O after each interval of work, which blocks them for a time.
+type ioModel struct {
+	// every is the work a process does between I/O requests, at full speed; zero never blocks.
+	every int64
+	time  int64
+}
+
+// readyQueue returns an empty ready queue of the processes admitted by src, in the discipline's order,
+// given the remaining work of each process and the time.
+func (d discipline) readyQueue(src arrivals, remaining func(i int) int64, now func() int64) readyQueue {
+	if d.queue != nil {
+		return d.queue(src, remaining, now)
+	}
+	if d.less == nil {
+		return &fifoQueue{}
+	}
+	return newHeapQueue(func(a, b int) bool {
+		p, q := src.process(a), src.process(b)
+		switch {
+		case d.less(p, q):
+			return true
+		case d.less(q, p), len(d.ties) == 0:
+			return false
+		}
+		return d.ties.compare(p, q, remaining(a), remaining(b)) < 0
+	})
+}
+
+// schedule runs processes on a CPU by the discipline, admitting them into memory.
+func (d discipline) schedule(processes []Process, memory memoryConfig, cpu cpuModel) Result {
+	res := Result{
+		Gantt:          make([]TimeSlice, 0),
+		Stats:          make([]ProcessStats, len(processes)),
+		MemoryCapacity: memory.capacity,
+		CPU:            cpu,
+	}
+	d.simulate(newAdmission(processes, memory), 1, cpu,
+		func(slice TimeSlice) { res.Gantt = append(res.Gantt, slice) },
+		func(io TimeSlice) { res.IO = append(res.IO, io) },
+		func(i int, s ProcessStats) { res.Stats[i] = s })
+
+	return res
+}
+
+// simulate runs the processes admitted by src on cpus CPUs as a discrete-event simulation, passing each slice
+// to onSlice as it starts, each time a process blocks on I/O to onIO, and the timing of each process to
+// onExit as it completes.
+// The clock jumps from one event to the next, a CPU finishing a slice, a process returning from I/O or a
+// process arriving. At each event, processes that have finished their slice go back to the ready queue, by CPU,
+// followed by processes returning from I/O and then newly admitted processes, in the order of the tie-break
+// chain (or ahead of them, if arrivals go first), and then idle CPUs run the next ready processes, by CPU.
+// If src is a clock, each jump waits on it, and processes can arrive in between.
+func (d discipline) simulate(src arrivals, cpus int, cpu cpuModel, onSlice, onIO func(TimeSlice), onExit func(i int, s ProcessStats)) {
+	var (
+		now       int64
+		remaining []int64
+		ready     = d.readyQueue(src, func(i int) int64 { return remaining[i] }, func() int64 { return now })
+		aux       fifoQueue
+		ran       []int64
+		// sinceIO is the work each process has done since it last blocked on I/O, blocked how long it has been
+		// blocked, and used the work of its slice a virtual process did before I/O cut it short.
+		sinceIO    []int64
+		blocked    []int64
+		used       []int64
+		running    = make([]int, cpus)
+		idle       = cpus
+		events     = make(calendar, 0, cpus+1)
+		arrivalDue bool
+		preempted  []int
+		rounds     []int64
+		roundLeft  int
+	)
+	for c := range running {
+		running[c] = -1
+	}
+
+	for {
+		admitted := src.admit(now)
+		d.ties.sort(admitted, src)
+		for _, i := range admitted {
+			for i >= len(remaining) {
+				remaining, ran = append(remaining, 0), append(ran, 0)
+				sinceIO, blocked, used = append(sinceIO, 0), append(blocked, 0), append(used, 0)
+			}
+			remaining[i], ran[i] = src.process(i).BurstDuration, 0
+			sinceIO[i], blocked[i], used[i] = 0, 0, 0
+			ready.push(i)
+		}
+		for _, i := range preempted {
+			ready.push(i)
+		}
+		preempted = preempted[:0]
+
+		// Run the next ready processes on the idle CPUs
+		for c := 0; c < cpus && idle > 0 && ready.len()+aux.len() > 0; c++ {
+			if running[c] >= 0 {
+				continue
+			}
+			var i int
+			if aux.len() > 0 {
+				i = aux.pop()
+			} else {
+				if d.round != nil && roundLeft == 0 {
+					rounds = rounds[:0]
+					ready.each(func(i int) { rounds = append(rounds, remaining[i]) })
+					d.round(rounds)
+					roundLeft = ready.len()
+				}
+				roundLeft--
+				i = ready.pop()
+			}
+			p := src.process(i)
+			// A virtual process back from I/O only runs for the rest of its slice
+			before := used[i]
+			work, level := d.run(p, remaining[i]+before, ready.len()+aux.len())
+			work -= before
+			used[i] = 0
+			if d.io.every > 0 && work > d.io.every-sinceIO[i] {
+				work = d.io.every - sinceIO[i]
+				if d.virtual {
+					used[i] = before + work
+				}
+			}
+			duration := cpu.stretch(work, level)
+			onSlice(TimeSlice{
+				PID:   p.ProcessID,
+				Start: now,
+				Stop:  now + duration,
+				CPU:   c,
+				Level: level,
+			})
+			remaining[i] -= work
+			ran[i] += duration
+			sinceIO[i] += work
+			running[c] = i
+			idle--
+			heap.Push(&events, event{at: now + duration, cpu: c})
+		}
+
+		// Admit processes as they arrive, even while every CPU is busy, so they queue ahead of those preempted later
+		if !arrivalDue && src.arriving() {
+			heap.Push(&events, event{at: src.nextArrival(), cpu: arrivalEvent})
+			arrivalDue = true
+		}
+		next := int64(-1)
+		if len(events) > 0 {
+			next = events[0].at
+		}
+		if c, ok := src.(clock); ok {
+			next = c.wait(now, next)
+		}
+		if next < 0 {
+			return
+		}
+
+		now = next
+		for len(events) > 0 && events[0].at == now {
+			e := heap.Pop(&events).(event)
+			switch e.cpu {
+			case arrivalEvent:
+				arrivalDue = false
+				continue
+			case ioReturnEvent:
+				if d.virtual {
+					aux.push(e.slot)
+				} else {
+					ready.push(e.slot)
+				}
+				continue
+			}
+			i := running[e.cpu]
+			running[e.cpu] = -1
+			idle++
+			if remaining[i] > 0 && d.io.every > 0 && sinceIO[i] == d.io.every {
+				sinceIO[i] = 0
+				blocked[i] += d.io.time
+				onIO(TimeSlice{PID: src.process(i).ProcessID, Start: now, Stop: now + d.io.time})
+				heap.Push(&events, event{at: now + d.io.time, cpu: ioReturnEvent, slot: i})
+				continue
+			}
+			if remaining[i] > 0 {
+				if d.arrivalsFirst {
+					preempted = append(preempted, i)
+				} else {
+					ready.push(i)
+				}
+				continue
+			}
+
+			// The process has completed, so it frees its memory and the processes that depend on it
+			p := src.process(i)
+			turnaround := now - p.ArrivalTime
+			stats := ProcessStats{
+				Process:    *p,
+				Admission:  src.delay(i),
+				Wait:       turnaround - ran[i] - blocked[i],
+				IO:         blocked[i],
+				Turnaround: turnaround,
+				Exit:       now,
+			}
+			src.complete(i, now)
+			onExit(i, stats)
+		}
+	}
+}
+
+// fullBurst runs each process to completion at full speed.
+func fullBurst(_ *Process, remaining int64, _ int) (int64, int) {
+	return remaining, 0
+}
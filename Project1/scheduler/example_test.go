@@ -0,0 +1,35 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+func Example() {
+	p := scheduler.DefaultParams()
+	if err := p.Set("quantum", "3"); err != nil {
+		log.Fatal(err)
+	}
+	processes, err := scheduler.Load(strings.NewReader("1,5,0\n2,9,3\n3,6,6"), "csv", p)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pol, err := scheduler.LookupPolicy("rr")
+	if err != nil {
+		log.Fatal(err)
+	}
+	res, err := pol.Schedule(processes, p)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range res.Stats {
+		fmt.Printf("process %d waits %d and exits at %d\n", s.ProcessID, s.Wait, s.Exit)
+	}
+	// Output:
+	// process 1 waits 0 and exits at 5
+	// process 2 waits 8 and exits at 20
+	// process 3 waits 5 and exits at 17
+}
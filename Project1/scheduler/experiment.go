@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Experiment runs a workload through a list of policies, each with its own parameters, and writes the
+	// results to its outputs, so an experiment can be version-controlled and repeated. It's read from a YAML or
+	// JSON experiment file, e.g.
+	//
+	//	workload:
+	//	  file: processes.csv
+	//	params:
+	//	  unit: ms
+	//	runs:
+	//	  - policy: rr
+	//	    params: {quantum: 2}
+	//	  - policy: rr
+	//	    params: {quantum: 4, cpus: 2}
+	//	  - policy: sjf
+	//	outputs:
+	//	  - format: text
+	//	  - file: results.json
+	//	    format: json
+	//	verify: true
+	//
+	// Params are set by the names of the command-line flags, and apply to every run unless a run sets them.
+	// File paths are relative to the experiment file, and an output with no file is written to stdout.
+	Experiment struct {
+		Workload Workload          `yaml:"workload"`
+		Params   map[string]string `yaml:"params"`
+		Runs     []Run             `yaml:"runs"`
+		Outputs  []Output          `yaml:"outputs"`
+		// Verify checks every schedule against the scheduling invariants, failing the experiment if one breaks.
+		Verify bool `yaml:"verify"`
+		// dir is the directory of the experiment file, which file paths are relative to.
+		dir string
+	}
+	// Workload is the scheduling file of an experiment and its input format, "csv" unless it's set.
+	Workload struct {
+		File   string `yaml:"file"`
+		Format string `yaml:"format"`
+	}
+	// Run is a policy with its parameters. Name defaults to the policy's title and the parameters it sets.
+	Run struct {
+		Name   string            `yaml:"name"`
+		Policy string            `yaml:"policy"`
+		Params map[string]string `yaml:"params"`
+	}
+	// Output is a file the results of an experiment are written to in a format, "text" (the rendered report of
+	// each run, the default), "markdown" or "latex" (each run's chart and table for a document) or "json" (an
+	// array of each run's name, policy, parameters and schedule).
+	Output struct {
+		File   string `yaml:"file"`
+		Format string `yaml:"format"`
+	}
+	// runJSON is the result of a run in a JSON output.
+	runJSON struct {
+		Name     string            `json:"name"`
+		Policy   string            `json:"policy"`
+		Params   map[string]string `json:"params,omitempty"`
+		Schedule Result            `json:"schedule"`
+	}
+)
+
+// timeScaleParams set how the workload is read, so they apply to the whole experiment rather than a run.
+var timeScaleParams = map[string]bool{"unit": true, "precision": true, "tick": true}
+
+// LoadExperiment reads and validates an experiment file.
+func LoadExperiment(path string) (Experiment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Experiment{}, fmt.Errorf("%v: error opening experiment file", err)
+	}
+	defer f.Close()
+
+	return readExperiment(f, filepath.Dir(path))
+}
+
+// readExperiment reads an experiment in YAML or JSON, with file paths relative to dir.
+func readExperiment(r io.Reader, dir string) (Experiment, error) {
+	e := Experiment{dir: dir}
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&e); err != nil && err != io.EOF {
+		return Experiment{}, fmt.Errorf("%w: experiment: %v", ErrInvalidArgs, err)
+	}
+	if err := e.validate(); err != nil {
+		return Experiment{}, err
+	}
+
+	return e, nil
+}
+
+// validate returns an error if the experiment can't be run, and names its runs.
+func (e *Experiment) validate() error {
+	if e.Workload.File == "" {
+		return fmt.Errorf("%w: experiment has no workload file", ErrInvalidArgs)
+	}
+	if e.Workload.Format == "" {
+		e.Workload.Format = "csv"
+	}
+	if _, ok := inputFormats[e.Workload.Format]; !ok {
+		return fmt.Errorf("%w: unknown input format %q", ErrInvalidArgs, e.Workload.Format)
+	}
+	if len(e.Runs) == 0 {
+		return fmt.Errorf("%w: experiment has no runs", ErrInvalidArgs)
+	}
+	if _, err := e.params(nil); err != nil {
+		return err
+	}
+	for i := range e.Runs {
+		run := &e.Runs[i]
+		pol, err := LookupPolicy(run.Policy)
+		if err != nil {
+			return fmt.Errorf("run %d: %w", i+1, err)
+		}
+		for name := range run.Params {
+			if timeScaleParams[name] {
+				return fmt.Errorf("%w: run %d: %s must be set for the whole experiment", ErrInvalidArgs, i+1, name)
+			}
+		}
+		if _, err := e.params(run.Params); err != nil {
+			return fmt.Errorf("run %d: %w", i+1, err)
+		}
+		if run.Name == "" {
+			run.Name = pol.Title()
+			if len(run.Params) > 0 {
+				run.Name += " (" + formatParams(run.Params) + ")"
+			}
+		}
+	}
+	if len(e.Outputs) == 0 {
+		e.Outputs = []Output{{Format: "text"}}
+	}
+	for i := range e.Outputs {
+		out := &e.Outputs[i]
+		if out.Format == "" {
+			out.Format = "text"
+		}
+		if _, ok := reportFormats[out.Format]; !ok && out.Format != "json" {
+			return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, out.Format)
+		}
+	}
+
+	return nil
+}
+
+// params returns the parameters of the experiment overridden by those of a run.
+func (e Experiment) params(run map[string]string) (Params, error) {
+	p := DefaultParams()
+	for _, params := range []map[string]string{e.Params, run} {
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := p.Set(name, params[name]); err != nil {
+				return Params{}, err
+			}
+		}
+	}
+	if err := p.Parse(); err != nil {
+		return Params{}, err
+	}
+
+	return p, nil
+}
+
+// formatParams formats parameters as "name=value" pairs in order of name, e.g. "cpus=2, quantum=4".
+func formatParams(params map[string]string) string {
+	pairs := make([]string, 0, len(params))
+	for name, value := range params {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// path resolves a file path of the experiment against the directory of the experiment file.
+func (e Experiment) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(e.dir, name)
+}
+
+// Run schedules the workload with each run and writes the results to every output, writing outputs with no
+// file to stdout.
+func (e Experiment) Run(stdout io.Writer) error {
+	p, err := e.params(nil)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(e.path(e.Workload.File))
+	if err != nil {
+		return fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	processes, err := Load(f, e.Workload.Format, p)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	runs := make([]runJSON, len(e.Runs))
+	for i, run := range e.Runs {
+		pol, err := LookupPolicy(run.Policy)
+		if err != nil {
+			return err
+		}
+		p, err := e.params(run.Params)
+		if err != nil {
+			return err
+		}
+		res, err := pol.Schedule(processes, p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", run.Name, err)
+		}
+		if e.Verify {
+			if err := pol.Verify(processes, res); err != nil {
+				return fmt.Errorf("%s: %w", run.Name, err)
+			}
+		}
+		runs[i] = runJSON{Name: run.Name, Policy: run.Policy, Params: run.Params, Schedule: res}
+	}
+
+	for _, out := range e.Outputs {
+		if err := e.write(out, stdout, runs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write writes the results of the runs to an output, as JSON or rendered in a report format.
+func (e Experiment) write(out Output, stdout io.Writer, runs []runJSON) (err error) {
+	w := stdout
+	if out.File != "" && out.File != "-" {
+		f, err := os.Create(e.path(out.File))
+		if err != nil {
+			return fmt.Errorf("%v: error creating output file", err)
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil && cerr != nil {
+				err = fmt.Errorf("%v: error closing output file", cerr)
+			}
+		}()
+		w = f
+	}
+
+	if out.Format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+	for _, run := range runs {
+		if err := run.Schedule.RenderFormat(w, run.Name, out.Format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_readExperiment(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		experiment string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name: "yaml",
+			args: args{experiment: `
+workload: {file: processes.csv}
+params: {unit: ms, precision: 1}
+runs:
+  - policy: rr
+    params: {quantum: 2}
+  - policy: rr
+    params: {quantum: 1.5ms, cpus: 2}
+  - {name: SJF, policy: sjf}
+`},
+			wantNames: []string{"Round-robin (quantum=2)", "Round-robin (cpus=2, quantum=1.5ms)", "SJF"},
+		},
+		{
+			name:      "json",
+			args:      args{experiment: `{"workload": {"file": "processes.json", "format": "json"}, "runs": [{"policy": "priority"}]}`},
+			wantNames: []string{"Priority"},
+		},
+		{
+			name:    "empty",
+			args:    args{experiment: ``},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "no runs",
+			args:    args{experiment: `workload: {file: processes.csv}`},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown field",
+			args:    args{experiment: "workload: {file: processes.csv}\nruns: [{policy: rr, quantum: 2}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown policy",
+			args:    args{experiment: "workload: {file: processes.csv}\nruns: [{policy: lottery}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown parameter",
+			args:    args{experiment: "workload: {file: processes.csv}\nruns: [{policy: rr, params: {slice: 2}}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "invalid parameter",
+			args:    args{experiment: "workload: {file: processes.csv}\nparams: {quantum: 0}\nruns: [{policy: rr}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "time scale set by a run",
+			args:    args{experiment: "workload: {file: processes.csv}\nruns: [{policy: rr, params: {unit: ms}}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown input format",
+			args:    args{experiment: "workload: {file: processes.csv, format: xml}\nruns: [{policy: rr}]"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown output format",
+			args:    args{experiment: "workload: {file: processes.csv}\nruns: [{policy: rr}]\noutputs: [{format: html}]"},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := readExperiment(strings.NewReader(tt.args.experiment), ".")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readExperiment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(e.Runs) != len(tt.wantNames) {
+				t.Fatalf("readExperiment() has %d runs, want %d", len(e.Runs), len(tt.wantNames))
+			}
+			for i, want := range tt.wantNames {
+				if e.Runs[i].Name != want {
+					t.Errorf("run %d is named %q, want %q", i+1, e.Runs[i].Name, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExperiment_Run(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "processes.csv"), []byte("1,5,0,2\n2,9,3,1\n3,6,6,3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	experiment := `
+workload: {file: processes.csv}
+runs:
+  - policy: rr
+    params: {quantum: 2}
+  - policy: rr
+    params: {quantum: 3}
+outputs:
+  - format: text
+  - {file: results.json, format: json}
+verify: true
+`
+	path := filepath.Join(dir, "experiment.yaml")
+	if err := os.WriteFile(path, []byte(experiment), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := LoadExperiment(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	if err := e.Run(&stdout); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Round-robin (quantum=2)", "Round-robin (quantum=3)"} {
+		if !strings.Contains(stdout.String(), name) {
+			t.Errorf("text output doesn't have the run %q:\n%s", name, stdout.String())
+		}
+	}
+
+	results, err := os.ReadFile(filepath.Join(dir, "results.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var runs []struct {
+		Name     string
+		Params   map[string]string
+		Schedule struct {
+			Processes []struct{ Exit json.Number }
+		}
+	}
+	if err := json.Unmarshal(results, &runs); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[1].Params["quantum"] != "3" {
+		t.Fatalf("json output = %s, want both runs", results)
+	}
+	// Quantum 3 matches the package example
+	for i, want := range []string{"5", "20", "17"} {
+		if got := runs[1].Schedule.Processes[i].Exit.String(); got != want {
+			t.Errorf("process %d exits at %s with quantum 3, want %s", i+1, got, want)
+		}
+	}
+}
+
+func TestExampleExperiment(t *testing.T) {
+	t.Parallel()
+	e, err := LoadExperiment(filepath.Join("..", "example_experiment.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	if err := e.Run(&stdout); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), "Round-robin, long quantum") {
+		t.Errorf("output doesn't have the named run:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,261 @@
+package scheduler
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ownerShares weighs the CPU fair-share scheduling gives each owner, as the -shares flag of owner:weight pairs,
+// e.g. "alice:2,bob:1"; an owner it doesn't name weighs 1.
+type ownerShares map[string]int64
+
+func (s ownerShares) String() string {
+	owners := make([]string, 0, len(s))
+	for owner := range s {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for i, owner := range owners {
+		owners[i] = fmt.Sprintf("%s:%d", owner, s[owner])
+	}
+	return strings.Join(owners, ",")
+}
+
+func (s *ownerShares) Set(value string) error {
+	shares := make(ownerShares)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		owner, weight, ok := strings.Cut(pair, ":")
+		if !ok {
+			return fmt.Errorf("share %q must be owner:weight", pair)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(weight), 10, 64)
+		if err != nil || n < 1 {
+			return fmt.Errorf("weight of %q must be a positive integer, got %q", owner, weight)
+		}
+		shares[strings.TrimSpace(owner)] = n
+	}
+	*s = shares
+	return nil
+}
+
+// weight returns the weight of an owner.
+func (s ownerShares) weight(owner string) int64 {
+	if w, ok := s[owner]; ok {
+		return w
+	}
+	return 1
+}
+
+// fairShare divides the CPU among the owners of the ready processes by their weights, and then equally among
+// each owner's processes, preempting each after at most the time quantum, so an owner with many processes
+// gets no more of the CPU than one with a single process. Processes with no owner share one.
+func fairShare(p Params) discipline {
+	d := roundRobinVariant(roundRobinDiscipline(p.timeQuantum), p)
+	d.queue = func(src arrivals, remaining func(i int) int64, _ func() int64) readyQueue {
+		return &fairShareQueue{
+			src:       src,
+			remaining: remaining,
+			quantum:   p.timeQuantum,
+			shares:    p.shares,
+			owners:    make(map[string]*ownerQueue),
+			popped:    make(map[int64]bool),
+		}
+	}
+	return d
+}
+
+// ownerQueue is the CPU an owner has been given, over its weight, the usage of its process that ran last, and
+// its queued processes.
+type ownerQueue struct {
+	usage float64
+	last  int64
+	// ready holds the owner's processes by the CPU they've been charged for.
+	ready queuedItems
+	// order holds the order each of the owner's processes was queued in, oldest first, with those popped yet
+	// to be trimmed from the front.
+	order []int64
+	// index is the owner's position in the heap of owners, or -1 if it has no processes queued.
+	index int
+}
+
+// owners implements heap.Interface for the owners with processes queued, by usage, then the first queued.
+type owners []*ownerQueue
+
+func (h owners) Len() int { return len(h) }
+
+func (h owners) Less(a, b int) bool {
+	if h[a].usage != h[b].usage {
+		return h[a].usage < h[b].usage
+	}
+	return h[a].order[0] < h[b].order[0]
+}
+
+func (h owners) Swap(a, b int) {
+	h[a], h[b] = h[b], h[a]
+	h[a].index, h[b].index = a, b
+}
+
+func (h *owners) Push(x any) {
+	o := x.(*ownerQueue)
+	o.index = len(*h)
+	*h = append(*h, o)
+}
+
+func (h *owners) Pop() any {
+	o := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	o.index = -1
+	return o
+}
+
+// fairShareQueue is a ready queue that pops the process that has run least of the owner that has run least
+// for its weight, the first queued of either when they're equal. As in the kernel's fair scheduler, an owner or
+// process that becomes ready starts no further behind than the one that ran last, so it can't claim the CPU
+// for the time it wasn't ready.
+type fairShareQueue struct {
+	src       arrivals
+	remaining func(i int) int64
+	quantum   int64
+	shares    ownerShares
+	owners    map[string]*ownerQueue
+	// ready holds the owners with processes queued, and n the processes.
+	ready owners
+	n     int
+	// seq is the order the last process was queued in, and popped the orders of those popped that are still
+	// to be trimmed from their owner's order.
+	seq    int64
+	popped map[int64]bool
+	// last is the usage of the owner that ran last.
+	last float64
+	// used is the CPU each process has been charged for, and charged the work of it that's been charged to its
+	// owner, by slot.
+	used, charged []int64
+}
+
+func (q *fairShareQueue) push(i int) {
+	proc := q.src.process(i)
+	o, ok := q.owners[proc.Owner]
+	if !ok {
+		o = &ownerQueue{ready: queuedItems{less: func(a, b int) bool { return q.used[a] < q.used[b] }}, index: -1}
+		q.owners[proc.Owner] = o
+	}
+	// Charge the owner for the work the process ran last, rather than the slice it was given, in case it
+	// blocked on I/O.
+	ran := proc.BurstDuration - q.remaining(i)
+	for len(q.used) <= i {
+		q.used, q.charged = append(q.used, 0), append(q.charged, 0)
+	}
+	if ran == 0 {
+		// The process is new, though its slot may have been another's that completed
+		q.used[i], q.charged[i] = 0, 0
+	}
+	o.usage += float64(ran-q.charged[i]) / float64(q.shares.weight(proc.Owner))
+	q.used[i] += ran - q.charged[i]
+	q.charged[i] = ran
+	if o.usage < q.last {
+		o.usage = q.last
+	}
+	if q.used[i] < o.last {
+		q.used[i] = o.last
+	}
+
+	q.seq++
+	heap.Push(&o.ready, queued{i: i, seq: q.seq})
+	o.order = append(o.order, q.seq)
+	if o.index < 0 {
+		heap.Push(&q.ready, o)
+	} else {
+		heap.Fix(&q.ready, o.index)
+	}
+	q.n++
+}
+
+func (q *fairShareQueue) pop() int {
+	owner := q.ready[0]
+	item := heap.Pop(&owner.ready).(queued)
+	i := item.i
+	q.popped[item.seq] = true
+	for len(owner.order) > 0 && q.popped[owner.order[0]] {
+		delete(q.popped, owner.order[0])
+		owner.order = owner.order[1:]
+	}
+	q.n--
+
+	// Charge the slice the process will run as it's popped, so the next CPU free sees it.
+	proc := q.src.process(i)
+	slice := mini(q.remaining(i), q.quantum)
+	q.last, owner.last = owner.usage, q.used[i]
+	owner.usage += float64(slice) / float64(q.shares.weight(proc.Owner))
+	q.used[i] += slice
+	q.charged[i] += slice
+	if owner.ready.Len() == 0 {
+		heap.Pop(&q.ready)
+	} else {
+		heap.Fix(&q.ready, 0)
+	}
+	return i
+}
+
+func (q *fairShareQueue) len() int {
+	return q.n
+}
+
+func (q *fairShareQueue) each(f func(i int)) {
+	for _, o := range q.ready {
+		for _, item := range o.ready.queue {
+			f(item.i)
+		}
+	}
+}
+
+// ownerShare returns the share of the CPU time used while each owner had processes in the system, from the
+// first arrival to the last exit of its processes, that went to its processes; nil if no process has an owner.
+func (r Result) ownerShare() map[string]float64 {
+	type span struct{ from, to int64 }
+	var (
+		owners = make(map[int64]string, len(r.Stats))
+		spans  = make(map[string]*span)
+		owned  bool
+	)
+	for _, s := range r.Stats {
+		owners[s.ProcessID] = s.Owner
+		owned = owned || s.Owner != ""
+		if sp, ok := spans[s.Owner]; ok {
+			sp.from, sp.to = mini(sp.from, s.ArrivalTime), maxi(sp.to, s.Exit)
+		} else {
+			spans[s.Owner] = &span{s.ArrivalTime, s.Exit}
+		}
+	}
+	if !owned {
+		return nil
+	}
+
+	share := make(map[string]float64, len(spans))
+	for owner, sp := range spans {
+		var own, busy int64
+		for _, slice := range r.Gantt {
+			if slice.Idle {
+				continue
+			}
+			overlap := mini(slice.Stop, sp.to) - maxi(slice.Start, sp.from)
+			if overlap <= 0 {
+				continue
+			}
+			busy += overlap
+			if owners[slice.PID] == owner {
+				own += overlap
+			}
+		}
+		if busy > 0 {
+			share[owner] = 100 * float64(own) / float64(busy)
+		}
+	}
+
+	return share
+}
@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ownerShares_Set(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   string
+		want    ownerShares
+		wantErr error
+	}{
+		{name: "pairs", value: "alice:2, bob:1", want: ownerShares{"alice": 2, "bob": 1}},
+		{name: "empty weighs every owner equally", value: "", want: ownerShares{}},
+		{name: "missing weight", value: "alice", wantErr: ErrInvalidArgs},
+		{name: "zero weight", value: "alice:0", wantErr: ErrInvalidArgs},
+		{name: "bad weight", value: "alice:two", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			if err := p.Set("shares", tt.value); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Set() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(p.shares, tt.want) {
+				t.Errorf("shares = %v, want %v", p.shares, tt.want)
+			}
+		})
+	}
+}
+
+// unitRuns returns the process that runs in each unit of time of a GANTT chart.
+func unitRuns(gantt []TimeSlice) []int64 {
+	var runs []int64
+	for _, slice := range gantt {
+		for t := slice.Start; t < slice.Stop; t++ {
+			runs = append(runs, slice.PID)
+		}
+	}
+	return runs
+}
+
+func Test_fairShare(t *testing.T) {
+	t.Parallel()
+	// Alice has one process, and bob three
+	owned := []Process{
+		{ProcessID: 1, BurstDuration: 3, Owner: "alice"},
+		{ProcessID: 2, BurstDuration: 1, Owner: "bob"},
+		{ProcessID: 3, BurstDuration: 1, Owner: "bob"},
+		{ProcessID: 4, BurstDuration: 1, Owner: "bob"},
+	}
+	tests := []struct {
+		name      string
+		processes []Process
+		shares    string
+		want      []int64
+	}{
+		{name: "owners share equally", processes: owned, want: []int64{1, 2, 3, 1, 4, 1}},
+		{name: "by weight", processes: owned, shares: "alice:3", want: []int64{1, 2, 1, 1, 3, 4}},
+		{
+			name: "processes with no owner share one",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 2, Owner: "alice"},
+			},
+			want: []int64{1, 3, 2, 3, 1, 2},
+		},
+		{
+			name: "a late owner starts level with the others",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Owner: "alice"},
+				{ProcessID: 2, ArrivalTime: 2, BurstDuration: 4, Owner: "bob"},
+			},
+			want: []int64{1, 1, 2, 1, 2, 1, 2, 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			for name, value := range map[string]string{"quantum": "1", "shares": tt.shares} {
+				if err := p.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pol, err := LookupPolicy("fair")
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := pol.Schedule(tt.processes, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pol.Verify(tt.processes, res); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+			if got := unitRuns(res.Gantt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule() runs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fairShare_stream(t *testing.T) {
+	t.Parallel()
+	// Process 3 arrives once process 1 has exited, taking its slot in the stream
+	const file = "id,burst,arrival,priority,owner\n1,4,0,1,alice\n2,20,0,1,bob\n3,10,6,1,alice\n4,10,8,1,bob\n"
+	p := DefaultParams()
+	var w bytes.Buffer
+	if err := streamSchedule(&w, strings.NewReader(file), "fair", p); err != nil {
+		t.Fatal(err)
+	}
+	processes, err := loadProcesses(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := fairShare(p).schedule(processes, p.memory, p.cpu)
+
+	exits := make(map[int64]streamExit)
+	lines := bufio.NewScanner(&w)
+	for lines.Scan() {
+		var exit streamExit
+		if err := json.Unmarshal(lines.Bytes(), &exit); err != nil {
+			t.Fatal(err)
+		}
+		if exit.Type == "exit" {
+			exits[exit.PID] = exit
+		}
+	}
+	for _, s := range res.Stats {
+		if got, want := fmt.Sprint(exits[s.ProcessID].Exit), p.time.format(s.Exit); got != want {
+			t.Errorf("process %d streamed exit %s, want %s", s.ProcessID, got, want)
+		}
+	}
+}
+
+func TestResult_ownerShare(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3, Owner: "alice"},
+		{ProcessID: 2, BurstDuration: 1, Owner: "bob"},
+		{ProcessID: 3, BurstDuration: 1, Owner: "bob"},
+		{ProcessID: 4, BurstDuration: 1, Owner: "bob"},
+	}
+	p := DefaultParams()
+	if err := p.Set("quantum", "1"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		policy string
+		want   map[string]float64
+	}{
+		{policy: "rr", want: map[string]float64{"alice": 50, "bob": 75}},
+		{policy: "fair", want: map[string]float64{"alice": 50, "bob": 60}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+			pol, err := LookupPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := pol.Schedule(processes, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.ownerShare(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ownerShare() = %v, want %v", got, tt.want)
+			}
+			table := res.table()
+			if got := table.header[1:3]; !reflect.DeepEqual(got, []string{"Owner", "Owner CPU share"}) {
+				t.Errorf("table() header = %v, want the owner and its share after the ID", table.header)
+			}
+			if got := table.rows[0][1:3]; !reflect.DeepEqual(got, []string{"alice", "50.0%"}) {
+				t.Errorf("table() row = %v, want alice with a 50.0%% share", table.rows[0])
+			}
+		})
+	}
+
+	t.Run("no owners", func(t *testing.T) {
+		t.Parallel()
+		res := roundRobin([]Process{{ProcessID: 1, BurstDuration: 2}}, memoryConfig{}, 1)
+		if got := res.ownerShare(); got != nil {
+			t.Errorf("ownerShare() = %v, want nil", got)
+		}
+		if got := res.table().header[1]; got != "Priority" {
+			t.Errorf("table() header = %v, want no owner columns", res.table().header)
+		}
+	})
+}
+
+func Test_loadProcesses_owner(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("id,burst,arrival,owner\n1,5,0, alice\n2,3,1,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processes[0].Owner != "alice" || processes[1].Owner != "" {
+		t.Errorf("loadProcesses() owners = %q and %q, want alice and none", processes[0].Owner, processes[1].Owner)
+	}
+
+	processes, err = loadJSONTrace(strings.NewReader(`[{"pid": 1, "arrival": 0, "burst": 5, "owner": "bob"}]`), timeScale{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processes[0].Owner != "bob" {
+		t.Errorf("loadJSONTrace() owner = %q, want bob", processes[0].Owner)
+	}
+}
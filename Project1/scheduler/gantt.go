@@ -0,0 +1,300 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// defaultGanttWidth is the columns a GANTT chart wraps at unless its style sets them.
+const defaultGanttWidth = 80
+
+// maxGanttWidth is the most columns a GANTT chart can wrap at, which bounds the size of its output.
+const maxGanttWidth = 1000
+
+// ganttStyle is how a GANTT chart is drawn in the terminal.
+type ganttStyle struct {
+	// width is the columns a chart wraps at; zero is defaultGanttWidth.
+	width int
+	// colour colours each process with ANSI escapes.
+	colour bool
+}
+
+// compressIdle is the fraction of a chart, as a divisor, that a stretch of idle time must be longer than to be
+// compressed to compressedWidth columns, if it would be wider, so that long gaps don't squeeze the rest of the chart.
+const (
+	compressIdle    = 5
+	compressedWidth = 5
+)
+
+// ganttColours are the ANSI background colours of processes, by their ID.
+var ganttColours = []int{41, 42, 43, 44, 45, 46}
+
+// paint returns text in the colour of a process when the style is coloured.
+func (s ganttStyle) paint(pid int64, text string) string {
+	if !s.colour {
+		return text
+	}
+	return fmt.Sprintf("\x1b[30;%dm%s\x1b[0m", ganttColours[(pid%6+6)%6], text)
+}
+
+// ganttSegment is a stretch of a CPU's chart: a process running for one or more consecutive slices, or idle.
+type ganttSegment struct {
+	pid         int64
+	start, stop int64
+	idle        bool
+	// compressed idle segments are drawn compressedWidth columns wide, whatever their time.
+	compressed bool
+	// width is the columns the segment is drawn in, including the bar it starts with.
+	width int
+}
+
+// label is what a segment is marked with.
+func (g ganttSegment) label() string {
+	if g.idle {
+		return ""
+	}
+	return fmt.Sprint(g.pid)
+}
+
+// draw returns the segment's bar and its body: the label, centred, or dots if it's idle, broken if it's compressed.
+func (g ganttSegment) draw(style ganttStyle) string {
+	body := g.width - 1
+	if g.compressed {
+		return "|" + strings.Repeat(".", (body-2)/2) + "//" + strings.Repeat(".", body-2-(body-2)/2)
+	}
+	if g.idle {
+		return "|" + strings.Repeat(".", body)
+	}
+	label := g.label()
+	left := (body - len(label)) / 2
+	return "|" + style.paint(g.pid, strings.Repeat(" ", left)+label+strings.Repeat(" ", body-left-len(label)))
+}
+
+// ganttSegments returns the segments of the slices of one CPU, in the order they start: consecutive slices of
+// the same process merge into one segment, and any time the CPU runs nothing before the last slice is idle.
+func ganttSegments(row []TimeSlice) []ganttSegment {
+	var (
+		segments []ganttSegment
+		free     int64
+	)
+	add := func(g ganttSegment) {
+		if last := len(segments) - 1; last >= 0 && segments[last].stop == g.start &&
+			segments[last].idle == g.idle && (g.idle || segments[last].pid == g.pid) {
+			segments[last].stop = g.stop
+			return
+		}
+		segments = append(segments, g)
+	}
+	for _, slice := range row {
+		if slice.Start > free {
+			add(ganttSegment{start: free, stop: slice.Start, idle: true})
+		}
+		add(ganttSegment{pid: slice.PID, start: slice.Start, stop: slice.Stop, idle: slice.Idle})
+		free = maxi(free, slice.Stop)
+	}
+
+	return segments
+}
+
+// ganttRows returns the segments of each CPU's chart of a schedule, in the order they start.
+func ganttRows(gantt []TimeSlice) [][]ganttSegment {
+	var cpus int
+	for i := range gantt {
+		if gantt[i].CPU >= cpus {
+			cpus = gantt[i].CPU + 1
+		}
+	}
+	rows := make([][]ganttSegment, cpus)
+	for cpu := range rows {
+		row := make([]TimeSlice, 0)
+		for i := range gantt {
+			if gantt[i].CPU == cpu {
+				row = append(row, gantt[i])
+			}
+		}
+		sort.SliceStable(row, func(i, j int) bool { return row[i].Start < row[j].Start })
+		rows[cpu] = ganttSegments(row)
+	}
+
+	return rows
+}
+
+// outputGantt charts a schedule in proportion to time, with each CPU of a multi-core schedule on its own,
+// wrapping each chart at the style's width, followed by a legend.
+func outputGantt(w io.Writer, gantt []TimeSlice, scale timeScale, style ganttStyle) {
+	_, _ = fmt.Fprintln(w, scale.label("Gantt schedule"))
+
+	var end int64
+	for i := range gantt {
+		end = maxi(end, gantt[i].Stop)
+	}
+	width := style.width
+	if width == 0 {
+		width = defaultGanttWidth
+	}
+
+	var (
+		rows       = ganttRows(gantt)
+		pids       []int64
+		seen       = make(map[int64]bool)
+		idle, long bool
+	)
+	for cpu := range rows {
+		for i, segment := range rows[cpu] {
+			switch {
+			case segment.idle && float64(segment.stop-segment.start)*compressIdle > float64(end) &&
+				float64(segment.stop-segment.start)*float64(width-1) > compressedWidth*float64(end):
+				rows[cpu][i].compressed, long = true, true
+			case segment.idle:
+				idle = true
+			case !seen[segment.pid]:
+				seen[segment.pid] = true
+				pids = append(pids, segment.pid)
+			}
+		}
+	}
+	perTick := scaleGantt(rows, end, width)
+	for cpu, row := range rows {
+		if len(rows) > 1 {
+			_, _ = fmt.Fprintf(w, "CPU %d\n", cpu)
+		}
+		outputGanttRows(w, row, scale, style, width)
+	}
+	outputGanttLegend(w, pids, idle, long, perTick, scale, style)
+	_, _ = fmt.Fprintln(w)
+}
+
+// scaleGantt sets the width of each segment of the rows in proportion to its time, at the largest scale at which
+// every row fits in width columns, returning the columns per tick. When the rows can't fit even with the fewest
+// columns each segment needs, time is scaled to the width as if they were all proportional, and the rows wrap.
+func scaleGantt(rows [][]ganttSegment, end int64, width int) float64 {
+	if end == 0 {
+		return 1
+	}
+	fits := func(perTick float64) bool {
+		for _, row := range rows {
+			columns := 1
+			for i := range row {
+				columns += row[i].columns(perTick)
+			}
+			if columns > width {
+				return false
+			}
+		}
+		return true
+	}
+	// No scale is larger than the one at which the time a row doesn't compress spans the whole width
+	lo, hi := 0.0, math.Inf(1)
+	for _, row := range rows {
+		span, columns := end, width-1
+		for i := range row {
+			if row[i].compressed {
+				span -= row[i].stop - row[i].start
+				columns -= compressedWidth
+			}
+		}
+		if span > 0 {
+			hi = math.Min(hi, float64(maxInt(columns, 1))/float64(span))
+		}
+	}
+	switch {
+	case fits(hi) || !fits(lo):
+		lo = hi
+	default:
+		for i := 0; i < 32; i++ {
+			if mid := (lo + hi) / 2; fits(mid) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i].width = row[i].columns(lo)
+		}
+	}
+
+	return lo
+}
+
+// columns returns how many columns wide the segment is drawn at a scale, including its bar: in proportion to its
+// time, but wide enough for its label and, if it's idle, a dot.
+func (g ganttSegment) columns(perTick float64) int {
+	if g.compressed {
+		return compressedWidth
+	}
+	columns := int(math.Round(float64(g.stop-g.start) * perTick))
+	return maxInt(columns, maxInt(len(g.label())+1, 2))
+}
+
+// outputGanttRows draws segments on as many rows as they need to fit in width columns, each above the times
+// its segments start at and the time its last one stops at. Start times that would run into the one before are
+// left out.
+func outputGanttRows(w io.Writer, segments []ganttSegment, scale timeScale, style ganttStyle, width int) {
+	for len(segments) > 0 {
+		n, columns := 1, segments[0].width
+		for n < len(segments) && columns+segments[n].width < width {
+			columns += segments[n].width
+			n++
+		}
+
+		var (
+			bars strings.Builder
+			axis []byte
+		)
+		mark := func(at int, t int64) {
+			axis = append(axis, strings.Repeat(" ", at-len(axis))...)
+			axis = append(axis, scale.format(t)...)
+		}
+		at := 0
+		for _, segment := range segments[:n] {
+			bars.WriteString(segment.draw(style))
+			if at == 0 || at > len(axis) {
+				mark(at, segment.start)
+			}
+			at += segment.width
+		}
+		// The row always ends with the time its last segment stops, moved right if need be
+		bars.WriteString("|")
+		mark(maxInt(at, len(axis)+1), segments[n-1].stop)
+		_, _ = fmt.Fprintln(w, bars.String())
+		_, _ = fmt.Fprintln(w, string(axis))
+		segments = segments[n:]
+	}
+}
+
+// outputGanttLegend names the processes of a chart, in the order they first run, in their colours, the idle
+// markers the chart has, and the time each column stands for.
+func outputGanttLegend(w io.Writer, pids []int64, idle, long bool, perTick float64, scale timeScale, style ganttStyle) {
+	entries := make([]string, len(pids))
+	for i, pid := range pids {
+		entries[i] = fmt.Sprint(pid)
+		if style.colour {
+			entries[i] = style.paint(pid, " "+entries[i]+" ")
+		}
+	}
+	legend := "Legend: " + strings.Join(entries, " ") + " process IDs"
+	if idle {
+		legend += ", .. idle"
+	}
+	if long {
+		legend += ", .//. long idle stretch, compressed"
+	}
+	column := scale.formatAverage(1 / perTick)
+	if scale.unit != "" {
+		column += " " + scale.unit
+	}
+	_, _ = fmt.Fprintf(w, "%s; one column is %s\n", legend, column)
+}
+
+// maxInt returns the maximum of two ints.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
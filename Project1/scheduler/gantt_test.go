@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func Test_ganttSegments(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		row  []TimeSlice
+		want []ganttSegment
+	}{
+		{
+			name: "consecutive slices of a process merge",
+			row:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 5}},
+			want: []ganttSegment{{pid: 1, start: 0, stop: 4}, {pid: 2, start: 4, stop: 5}},
+		},
+		{
+			name: "gaps are idle",
+			row:  []TimeSlice{{PID: 1, Start: 2, Stop: 4}, {PID: 1, Start: 6, Stop: 7}},
+			want: []ganttSegment{
+				{start: 0, stop: 2, idle: true}, {pid: 1, start: 2, stop: 4}, {start: 4, stop: 6, idle: true},
+				{pid: 1, start: 6, stop: 7},
+			},
+		},
+		{
+			name: "idle slices merge with gaps",
+			row:  []TimeSlice{{Start: 0, Stop: 2, Idle: true}, {PID: 3, Start: 3, Stop: 4}},
+			want: []ganttSegment{
+				{start: 0, stop: 3, idle: true}, {pid: 3, start: 3, stop: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ganttSegments(tt.row); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ganttSegments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputGantt(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		gantt []TimeSlice
+		style ganttStyle
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "proportional",
+			args: args{gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 6}}, style: ganttStyle{width: 13}},
+			want: "Gantt schedule\n" +
+				"| 1 |   2   |\n" +
+				"0   2       6\n" +
+				"Legend: 1 2 process IDs; one column is 0.50\n\n",
+		},
+		{
+			name: "wraps",
+			args: args{gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2}, {PID: 3, Start: 2, Stop: 3},
+				{PID: 1, Start: 3, Stop: 4},
+			}, style: ganttStyle{width: 6}},
+			want: "Gantt schedule\n" +
+				"|1|2|\n" +
+				"0 1 2\n" +
+				"|3|1|\n" +
+				"2 3 4\n" +
+				"Legend: 1 2 3 process IDs; one column is 0.80\n\n",
+		},
+		{
+			name: "compresses long idle stretches",
+			args: args{gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 100, Stop: 102}}, style: ganttStyle{width: 14}},
+			want: "Gantt schedule\n" +
+				"| 1 |.//.| 2 |\n" +
+				"0   2    100 102\n" +
+				"Legend: 1 2 process IDs, .//. long idle stretch, compressed; one column is 0.50\n\n",
+		},
+		{
+			name: "coloured",
+			args: args{gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 2, Stop: 3}}, style: ganttStyle{width: 8, colour: true}},
+			want: "Gantt schedule\n" +
+				"|\x1b[30;42m1\x1b[0m|.|\x1b[30;43m2\x1b[0m|\n" +
+				"0 1 2 3\n" +
+				"Legend: \x1b[30;42m 1 \x1b[0m \x1b[30;43m 2 \x1b[0m process IDs, .. idle; one column is 0.43\n\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			outputGantt(&w, tt.args.gantt, timeScale{}, tt.args.style)
+			if got := w.String(); got != tt.want {
+				t.Errorf("outputGantt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
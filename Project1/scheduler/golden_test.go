@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update regenerates the golden files under testdata instead of comparing against them:
+//
+//	go test ./Project1/scheduler -run TestGolden -update
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// goldenFormats renders a policy's schedule in every format that has golden files,
+// keyed by the golden file extension.
+var goldenFormats = map[string]func(w io.Writer, pol Policy, processes []Process, p Params){
+	"txt": func(w io.Writer, pol Policy, processes []Process, p Params) {
+		outputResult(w, pol.title, pol.schedule(processes, p))
+	},
+	"md": func(w io.Writer, pol Policy, processes []Process, p Params) {
+		outputMarkdown(w, pol.title, pol.schedule(processes, p))
+	},
+	"tex": func(w io.Writer, pol Policy, processes []Process, p Params) {
+		outputLaTeX(w, pol.title, pol.schedule(processes, p))
+	},
+}
+
+// TestGolden runs every policy over each workload in testdata/<workload>/processes.csv
+// and compares the output in each format against testdata/<workload>/<policy>.<format>.
+// A workload's policy parameters can be set with command-line flags in testdata/<workload>/flags.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+	workloads, err := filepath.Glob(filepath.Join("testdata", "*", "processes.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workloads) == 0 {
+		t.Fatal("no golden workloads found in testdata")
+	}
+	for _, workload := range workloads {
+		dir := filepath.Dir(workload)
+		p := loadGoldenParams(t, dir)
+		processes := loadGoldenProcesses(t, workload, p.time)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			t.Parallel()
+			for _, pol := range policies {
+				for ext, render := range goldenFormats {
+					pol, ext, render := pol, ext, render
+					t.Run(pol.name+"."+ext, func(t *testing.T) {
+						t.Parallel()
+						var w bytes.Buffer
+						render(&w, pol, processes, p)
+						assertGolden(t, filepath.Join(dir, pol.name+"."+ext), w.String())
+					})
+				}
+			}
+		})
+	}
+}
+
+func loadGoldenProcesses(t *testing.T, name string, scale timeScale) []Process {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	processes, err := loadScaledProcesses(f, scale)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	return processes
+}
+
+// loadGoldenParams returns the default policy parameters overridden by the workload's flags file, if any.
+func loadGoldenParams(t *testing.T, dir string) Params {
+	t.Helper()
+	p := DefaultParams()
+	b, err := os.ReadFile(filepath.Join(dir, "flags"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return p
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet(dir, flag.ContinueOnError)
+	p.Flags(flags)
+	if err := flags.Parse(strings.Fields(string(b))); err != nil {
+		t.Fatalf("%s: %v", dir, err)
+	}
+	if err := p.Parse(); err != nil {
+		t.Fatalf("%s: %v", dir, err)
+	}
+
+	return p
+}
+
+// assertGolden compares got against the golden file, or rewrites the golden file with -update.
+func assertGolden(t *testing.T, golden, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing golden file %s: run with -update to create it", golden)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(want), got, "output differs from %s: run with -update if the change is intended", golden)
+}
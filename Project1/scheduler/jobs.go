@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JobStats rolls up the timing of every thread of a job.
+type JobStats struct {
+	JobID   int64
+	Threads int
+	// Burst and Wait are totals over the job's threads.
+	Burst int64
+	Wait  int64
+	// Arrival is when the job's first thread arrives, and Exit when its last thread finishes.
+	Arrival    int64
+	Turnaround int64
+	Exit       int64
+}
+
+// jobs groups the processes into jobs in the order each job first appears.
+// Processes without a job ID are each a job of their own.
+func jobs(processes []Process) [][]int {
+	var (
+		groups [][]int
+		index  = make(map[int64]int)
+	)
+	for i, p := range processes {
+		if p.JobID == 0 {
+			groups = append(groups, []int{i})
+			continue
+		}
+		if j, ok := index[p.JobID]; ok {
+			groups[j] = append(groups[j], i)
+			continue
+		}
+		index[p.JobID] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	return groups
+}
+
+// jobStats rolls up the timing of each job in a schedule, leaving out processes without a job ID.
+func (r Result) jobStats() []JobStats {
+	var (
+		stats = make([]JobStats, 0)
+		index = make(map[int64]int)
+	)
+	for _, s := range r.Stats {
+		if s.JobID == 0 {
+			continue
+		}
+		i, ok := index[s.JobID]
+		if !ok {
+			i = len(stats)
+			index[s.JobID] = i
+			stats = append(stats, JobStats{JobID: s.JobID, Arrival: s.ArrivalTime, Exit: s.Exit})
+		}
+		job := &stats[i]
+		job.Threads++
+		job.Burst += s.BurstDuration
+		job.Wait += s.Wait
+		job.Arrival = mini(job.Arrival, s.ArrivalTime)
+		job.Exit = maxi(job.Exit, s.Exit)
+		job.Turnaround = job.Exit - job.Arrival
+	}
+
+	return stats
+}
+
+// gang schedules whole jobs first-come, first-serve on cpus CPUs, starting all of a job's threads together.
+// A job arrives once all of its threads have arrived, and starts when enough CPUs are free for its threads
+// (at most all of them) without overtaking earlier jobs; threads beyond the job's CPUs run as its CPUs free up.
+// A job that depends on a later job is overtaken by it, and a thread that depends on another of its job's
+// threads starts once that thread exits. Jobs that arrive together are ordered by the tie-break chain on their
+// first threads.
+// With a memory capacity, a job is admitted into memory as it starts, once there is memory for all of its
+// threads, which each hold their memory until they exit; a job that needs more memory than there is runs as many
+// of its threads as fit together, and the rest of them later.
+func gang(processes []Process, cpus int, memory memoryConfig, ties tieBreak) Result {
+	var (
+		groups  = jobs(processes)
+		arrival = make([]int64, len(groups))
+		free    = make([]int64, cpus)
+		index   = processIndex(processes)
+		exited  = make(map[int]int64, len(processes))
+		start   int64
+		pool    = newMemoryPool(memory)
+		// resident are the threads holding memory, to be freed as they exit.
+		resident []int
+		res      = Result{
+			Gantt:          make([]TimeSlice, 0),
+			Stats:          make([]ProcessStats, len(processes)),
+			MemoryCapacity: memory.capacity,
+		}
+	)
+	if cpus < 1 {
+		return res
+	}
+	for j, threads := range groups {
+		for _, i := range threads {
+			arrival[j] = maxi(arrival[j], processes[i].ArrivalTime)
+		}
+	}
+	order := make([]int, len(groups))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ja, jb := order[a], order[b]
+		if arrival[ja] != arrival[jb] {
+			return arrival[ja] < arrival[jb]
+		}
+		p, q := &processes[groups[ja][0]], &processes[groups[jb][0]]
+		return ties.compare(p, q, p.BurstDuration, q.BurstDuration) < 0
+	})
+
+	// runnable returns the threads whose dependencies have exited or are among the threads returned.
+	runnable := func(threads []int) []int {
+		in := make(map[int]bool, len(threads))
+		for added := true; added; {
+			added = false
+			for _, i := range threads {
+				ok := !in[i]
+				for _, dep := range processes[i].DependsOn {
+					if _, done := exited[index[dep]]; !done && !in[index[dep]] {
+						ok = false
+					}
+				}
+				if ok {
+					in[i], added = true, true
+				}
+			}
+		}
+		run := make([]int, 0, len(in))
+		for _, i := range threads {
+			if in[i] {
+				run = append(run, i)
+			}
+		}
+		return run
+	}
+
+	for len(order) > 0 {
+		// Take the earliest job that only waits for jobs that have run. If every job waits for another that
+		// waits for it in turn, run the part of the earliest job that can run, and the rest of it later.
+		var (
+			next    = -1
+			threads []int
+		)
+		for n, j := range order {
+			run := runnable(groups[j])
+			if len(run) == len(groups[j]) {
+				next, threads = n, run
+				break
+			}
+			if next < 0 && len(run) > 0 {
+				next, threads = n, run
+			}
+		}
+		j := order[next]
+		threads = dependencyOrder(processes, index, threads)
+		if memory.capacity > 0 {
+			var size int64
+			for n, i := range threads {
+				if size += processes[i].Memory; size > memory.capacity && n > 0 {
+					threads = threads[:n]
+					break
+				}
+			}
+		}
+		if len(threads) == len(groups[j]) {
+			order = append(order[:next], order[next+1:]...)
+		} else {
+			rest := make([]int, 0, len(groups[j])-len(threads))
+			for _, i := range groups[j] {
+				if !containsInt(threads, i) {
+					rest = append(rest, i)
+				}
+			}
+			groups[j] = rest
+		}
+
+		// Take the CPUs that free up first, waiting until enough of them are free for the gang
+		gangCPUs := make([]int, cpus)
+		for c := range gangCPUs {
+			gangCPUs[c] = c
+		}
+		sort.SliceStable(gangCPUs, func(a, b int) bool { return free[gangCPUs[a]] < free[gangCPUs[b]] })
+		gangCPUs = gangCPUs[:mini(int64(len(threads)), int64(cpus))]
+		start = maxi(start, arrival[j])
+		for _, c := range gangCPUs {
+			start = maxi(start, free[c])
+		}
+		for _, i := range threads {
+			for _, dep := range processes[i].DependsOn {
+				if exit, ok := exited[index[dep]]; ok {
+					start = maxi(start, exit)
+				}
+			}
+		}
+		if memory.capacity > 0 {
+			start, resident = admitGang(processes, &pool, threads, resident, exited, start)
+		}
+		for _, c := range gangCPUs {
+			free[c] = start
+		}
+
+		// Run each thread on whichever of the gang's CPUs frees up first, once its dependencies have exited
+		for _, i := range threads {
+			c := gangCPUs[0]
+			for _, other := range gangCPUs[1:] {
+				if free[other] < free[c] {
+					c = other
+				}
+			}
+			p := processes[i]
+			threadStart := free[c]
+			for _, dep := range p.DependsOn {
+				threadStart = maxi(threadStart, exited[index[dep]])
+			}
+			res.Gantt = append(res.Gantt, TimeSlice{
+				PID:   p.ProcessID,
+				Start: threadStart,
+				Stop:  threadStart + p.BurstDuration,
+				CPU:   c,
+			})
+			free[c] = threadStart + p.BurstDuration
+			exited[i] = free[c]
+			res.Stats[i] = ProcessStats{
+				Process:    p,
+				Wait:       free[c] - p.ArrivalTime - p.BurstDuration,
+				Turnaround: free[c] - p.ArrivalTime,
+				Exit:       free[c],
+			}
+			if memory.capacity > 0 {
+				res.Stats[i].Admission = start - p.ArrivalTime
+			}
+		}
+	}
+
+	return res
+}
+
+// admitGang allocates memory to the threads of a gang starting at start, or as soon after it as the threads
+// holding memory exit and free enough, returning when the gang is admitted and the threads holding memory.
+// Jobs start in time order, so the threads that have exited by then are freed first.
+func admitGang(processes []Process, pool *memoryPool, threads, resident []int, exited map[int]int64,
+	start int64) (int64, []int) {
+	sort.SliceStable(resident, func(a, b int) bool { return exited[resident[a]] < exited[resident[b]] })
+	for {
+		for len(resident) > 0 && exited[resident[0]] <= start {
+			pool.release(resident[0])
+			resident = resident[1:]
+		}
+		admitted := 0
+		for _, i := range threads {
+			if processes[i].Memory > 0 && !pool.reserve(i, processes[i].Memory) {
+				break
+			}
+			admitted++
+		}
+		// A thread that needs more memory than there is, which Schedule rejects, runs without any
+		if admitted == len(threads) || len(resident) == 0 {
+			return start, append(resident, threads[:admitted]...)
+		}
+		for _, i := range threads[:admitted] {
+			pool.release(i)
+		}
+		// The gang fits once every thread holding memory has exited, as it fits in the whole of it
+		start = exited[resident[0]]
+	}
+}
+
+// dependencyOrder returns the threads of a job with each thread after the threads of the job it depends on,
+// otherwise keeping their order.
+func dependencyOrder(processes []Process, index map[int64]int, threads []int) []int {
+	var (
+		ordered = make([]int, 0, len(threads))
+		placed  = make(map[int]bool, len(threads))
+	)
+	for len(ordered) < len(threads) {
+		for _, i := range threads {
+			if placed[i] {
+				continue
+			}
+			ready := true
+			for _, dep := range processes[i].DependsOn {
+				if containsInt(threads, index[dep]) && !placed[index[dep]] {
+					ready = false
+				}
+			}
+			if ready {
+				ordered = append(ordered, i)
+				placed[i] = true
+			}
+		}
+	}
+
+	return ordered
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// jobSection tables the timing of each job.
+func jobSection(stats []JobStats, ts timeScale) reportSection {
+	table := &scheduleTable{
+		header: []string{"Job", "Threads", ts.label("Burst"), ts.label("Arrival"), ts.label("Wait"), ts.label("Turnaround"), ts.label("Exit")},
+	}
+	for _, s := range stats {
+		table.rows = append(table.rows, []string{
+			fmt.Sprint(s.JobID),
+			fmt.Sprint(s.Threads),
+			ts.format(s.Burst),
+			ts.format(s.Arrival),
+			ts.format(s.Wait),
+			ts.format(s.Turnaround),
+			ts.format(s.Exit),
+		})
+	}
+	return reportSection{title: "Job table", table: table}
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_gang(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		cpus      int
+		memory    int64
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantGantt []TimeSlice
+	}{
+		{
+			name: "threads start together",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, JobID: 1},
+					{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2, JobID: 1},
+					{ProcessID: 3, ArrivalTime: 1, BurstDuration: 3, JobID: 2},
+					{ProcessID: 4, ArrivalTime: 1, BurstDuration: 3, JobID: 2},
+				},
+				cpus: 2,
+			},
+			// Job 2 waits for both CPUs, although one is free at 2.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 4, Stop: 7, CPU: 1},
+				{PID: 4, Start: 4, Stop: 7, CPU: 0},
+			},
+		},
+		{
+			name: "job waits for its last thread",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, JobID: 1},
+					{ProcessID: 2, ArrivalTime: 3, BurstDuration: 2, JobID: 1},
+				},
+				cpus: 2,
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 3, Stop: 5, CPU: 0},
+				{PID: 2, Start: 3, Stop: 5, CPU: 1},
+			},
+		},
+		{
+			name: "more threads than CPUs",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, JobID: 1},
+					{ProcessID: 2, ArrivalTime: 0, BurstDuration: 1, JobID: 1},
+					{ProcessID: 3, ArrivalTime: 0, BurstDuration: 2, JobID: 1},
+					{ProcessID: 4, ArrivalTime: 0, BurstDuration: 1},
+				},
+				cpus: 2,
+			},
+			// The standalone process can't overtake the job, so it starts once the job has started.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3, CPU: 0},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 3, Start: 1, Stop: 3, CPU: 1},
+				{PID: 4, Start: 3, Stop: 4, CPU: 0},
+			},
+		},
+		{
+			name: "single CPU",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, JobID: 1},
+					{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2, JobID: 1},
+				},
+				cpus: 1,
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+			},
+		},
+		{
+			name: "job waits for memory",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Memory: 70},
+					{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2, Memory: 40},
+				},
+				cpus:   2,
+				memory: 100,
+			},
+			// A CPU is free at 0, but there's only memory for process 2 once process 1 exits.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4, CPU: 0},
+				{PID: 2, Start: 4, Stop: 6, CPU: 1},
+			},
+		},
+		{
+			name: "job needs more memory than there is",
+			args: args{
+				processes: []Process{
+					{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, JobID: 1, Memory: 40},
+					{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2, JobID: 1, Memory: 40},
+					{ProcessID: 3, ArrivalTime: 0, BurstDuration: 2, JobID: 1, Memory: 40},
+				},
+				cpus:   3,
+				memory: 100,
+			},
+			// The threads that fit together run first, and the last once they free their memory.
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2, CPU: 0},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 2, Stop: 4, CPU: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := gang(tt.args.processes, tt.args.cpus, memoryConfig{capacity: tt.args.memory, fit: "first"}, nil)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("gang() = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+			if err := verifySchedule(tt.args.processes, got, false); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestResult_jobStats(t *testing.T) {
+	t.Parallel()
+	res := Result{
+		Stats: []ProcessStats{
+			{Process: Process{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, JobID: 7}, Wait: 0, Turnaround: 4, Exit: 4},
+			{Process: Process{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3}, Wait: 3, Turnaround: 6, Exit: 7},
+			{Process: Process{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2, JobID: 7}, Wait: 6, Turnaround: 8, Exit: 10},
+		},
+	}
+	want := []JobStats{
+		{JobID: 7, Threads: 2, Burst: 6, Wait: 6, Arrival: 0, Turnaround: 10, Exit: 10},
+	}
+	if got := res.jobStats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("jobStats() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import "encoding/json"
+
+type (
+	// sliceJSON is a TimeSlice with times on a time scale.
+	sliceJSON struct {
+		PID   int64       `json:"pid"`
+		Start json.Number `json:"start"`
+		Stop  json.Number `json:"stop"`
+		CPU   int         `json:"cpu"`
+		Level int         `json:"level"`
+		Idle  bool        `json:"idle,omitempty"`
+	}
+	// processJSON is the timing of a process with times on a time scale.
+	processJSON struct {
+		PID        int64       `json:"pid"`
+		Priority   int64       `json:"priority"`
+		Owner      string      `json:"owner,omitempty"`
+		Burst      json.Number `json:"burst"`
+		Arrival    json.Number `json:"arrival"`
+		Admission  json.Number `json:"admission"`
+		Wait       json.Number `json:"wait"`
+		IO         json.Number `json:"io,omitempty"`
+		Turnaround json.Number `json:"turnaround"`
+		Exit       json.Number `json:"exit"`
+	}
+	// summaryJSON is the averages of the processes of a schedule and the energy it used.
+	summaryJSON struct {
+		Processes         int         `json:"processes"`
+		AverageWait       json.Number `json:"averageWait"`
+		AverageTurnaround json.Number `json:"averageTurnaround"`
+		Throughput        float64     `json:"throughput"`
+		Makespan          json.Number `json:"makespan"`
+		Energy            float64     `json:"energy"`
+		RunningEnergy     float64     `json:"runningEnergy"`
+		IdleEnergy        float64     `json:"idleEnergy"`
+	}
+	// resultJSON is a Result with times on its time scale.
+	resultJSON struct {
+		Unit      string        `json:"unit,omitempty"`
+		TieBreak  []string      `json:"tieBreak,omitempty"`
+		Gantt     []sliceJSON   `json:"gantt"`
+		IO        []sliceJSON   `json:"io,omitempty"`
+		Processes []processJSON `json:"processes"`
+		// OwnerShare is each owner's share of the CPU while it had processes in the system, as a percentage.
+		OwnerShare map[string]float64 `json:"ownerShare,omitempty"`
+		Summary    summaryJSON        `json:"summary"`
+	}
+)
+
+func (s timeScale) sliceJSON(slice TimeSlice) sliceJSON {
+	return sliceJSON{
+		PID:   slice.PID,
+		Start: json.Number(s.format(slice.Start)),
+		Stop:  json.Number(s.format(slice.Stop)),
+		CPU:   slice.CPU,
+		Level: slice.Level,
+		Idle:  slice.Idle,
+	}
+}
+
+func (s timeScale) processJSON(p ProcessStats) processJSON {
+	var io json.Number
+	if p.IO > 0 {
+		io = json.Number(s.format(p.IO))
+	}
+	return processJSON{
+		PID:        p.ProcessID,
+		Priority:   p.Priority,
+		Owner:      p.Owner,
+		Burst:      json.Number(s.format(p.BurstDuration)),
+		Arrival:    json.Number(s.format(p.ArrivalTime)),
+		Admission:  json.Number(s.format(p.Admission)),
+		Wait:       json.Number(s.format(p.Wait)),
+		IO:         io,
+		Turnaround: json.Number(s.format(p.Turnaround)),
+		Exit:       json.Number(s.format(p.Exit)),
+	}
+}
+
+// summaryJSON returns the summary of a schedule from the total wait and turnaround of its processes,
+// when the last one exits and the energy the CPUs used.
+func (s timeScale) summaryJSON(processes int, wait, turnaround float64, makespan int64, meter energyMeter) summaryJSON {
+	summary := summaryJSON{
+		Processes:         processes,
+		AverageWait:       json.Number(s.formatAverage(0)),
+		AverageTurnaround: json.Number(s.formatAverage(0)),
+		Makespan:          json.Number(s.format(makespan)),
+	}
+	if processes > 0 {
+		count := float64(processes)
+		summary.AverageWait = json.Number(s.formatAverage(wait / count))
+		summary.AverageTurnaround = json.Number(s.formatAverage(turnaround / count))
+		summary.Throughput = count / float64(makespan) * float64(s.perUnit())
+	}
+	summary.RunningEnergy, summary.IdleEnergy = meter.energy(makespan, s)
+	summary.Energy = summary.RunningEnergy + summary.IdleEnergy
+
+	return summary
+}
+
+// MarshalJSON encodes the schedule with its times as decimal numbers in the unit of its time scale:
+// the slices of the GANTT chart, the timing of each process, their averages and the energy used.
+func (r Result) MarshalJSON() ([]byte, error) {
+	var (
+		ts    = r.Time
+		meter = energyMeter{cpu: r.CPU, cpus: 1}
+		res   = resultJSON{
+			Unit:      ts.unit,
+			TieBreak:  r.TieBreak,
+			Gantt:     make([]sliceJSON, len(r.Gantt)),
+			Processes: make([]processJSON, len(r.Stats)),
+		}
+		wait, turnaround float64
+	)
+	for i, slice := range r.Gantt {
+		meter.add(slice)
+		res.Gantt[i] = ts.sliceJSON(slice)
+	}
+	for _, io := range r.IO {
+		res.IO = append(res.IO, ts.sliceJSON(io))
+	}
+	for i, s := range r.Stats {
+		wait += float64(s.Wait)
+		turnaround += float64(s.Turnaround)
+		res.Processes[i] = ts.processJSON(s)
+	}
+	res.OwnerShare = r.ownerShare()
+	res.Summary = ts.summaryJSON(len(r.Stats), wait, turnaround, r.makespan(), meter)
+
+	return json.Marshal(res)
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestResult_MarshalJSON(t *testing.T) {
+	t.Parallel()
+	res := Result{
+		Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 15}, {PID: 2, Start: 20, Stop: 45, Level: 1}},
+		Stats: []ProcessStats{
+			{Process: Process{ProcessID: 1, BurstDuration: 15, Priority: 2}, Turnaround: 15, Exit: 15},
+			{Process: Process{ProcessID: 2, ArrivalTime: 5, BurstDuration: 20}, Admission: 5, Wait: 15, Turnaround: 40, Exit: 45},
+		},
+		CPU:  cpuModel{states: []pstate{{speed: 100, power: 10}, {speed: 80, power: 4}}, idlePower: 1},
+		Time: timeScale{unit: "ms", precision: 1},
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got resultJSON
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := resultJSON{
+		Unit: "ms",
+		Gantt: []sliceJSON{
+			{PID: 1, Start: "0", Stop: "1.5"},
+			{PID: 2, Start: "2", Stop: "4.5", Level: 1},
+		},
+		Processes: []processJSON{
+			{PID: 1, Priority: 2, Burst: "1.5", Arrival: "0", Admission: "0", Wait: "0", Turnaround: "1.5", Exit: "1.5"},
+			{PID: 2, Burst: "2", Arrival: "0.5", Admission: "0.5", Wait: "1.5", Turnaround: "4", Exit: "4.5"},
+		},
+		Summary: summaryJSON{
+			Processes:         2,
+			AverageWait:       "0.75",
+			AverageTurnaround: "2.75",
+			Makespan:          "4.5",
+			Energy:            1.5*10 + 2.5*4 + 0.5*1,
+			RunningEnergy:     1.5*10 + 2.5*4,
+			IdleEnergy:        0.5 * 1,
+		},
+	}
+	// Throughput is two processes in 4.5ms
+	if math.Abs(got.Summary.Throughput-2/4.5) > 1e-9 {
+		t.Errorf("throughput = %v, want %v", got.Summary.Throughput, 2/4.5)
+	}
+	got.Summary.Throughput = 0
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalJSON() = %s, want %+v", b, want)
+	}
+}
@@ -0,0 +1,345 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LiveSession is a live simulation of one policy, which processes are submitted to while it runs: a process
+// arrives when it's submitted, or at the arrival time of its record if that's later. The clock runs at speed
+// times real time, each tick lasting as long as a tick of the time scale (-tick when times are unitless); at
+// speed 0 it jumps from event to event as fast as it can, and only stops to wait for processes to arrive.
+// Like streaming, it runs the policy on the discrete-event engine, without modelling memory.
+type LiveSession struct {
+	pol   Policy
+	p     Params
+	speed float64
+	start time.Time
+	// notify wakes the simulation when a process is submitted, or the session is closed.
+	notify chan struct{}
+
+	// mu guards the submissions, which come from other goroutines.
+	mu      sync.Mutex
+	columns []string
+	record  int
+	// incoming holds the processes submitted since the simulation last took them, with when they were.
+	incoming []submission
+	// ids holds the IDs of the processes submitted and not yet completed.
+	ids map[int64]bool
+	// end is the latest any process submitted so far can complete, at the slowest speed.
+	end    int64
+	closed bool
+
+	// The rest belong to the simulation: pending holds the processes taken from incoming that are yet to
+	// arrive, in arrival order, and the processes that have arrived hold slots reused once they complete.
+	pending []Process
+	slots   []Process
+	free    []int
+	log     io.Writer
+	running int64
+	blocked map[int64]int64
+}
+
+// submission is a process submitted to a live session, and when it was.
+type submission struct {
+	process Process
+	at      time.Time
+}
+
+// NewLiveSession returns a live session of the named policy with the parameters in p, which runs at speed
+// times real time once it's run.
+func NewLiveSession(policy string, p Params, speed float64) (*LiveSession, error) {
+	if err := p.Parse(); err != nil {
+		return nil, err
+	}
+	pol, err := LookupPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case pol.stream == nil:
+		return nil, fmt.Errorf("%w: the %s policy can't run live", ErrInvalidArgs, policy)
+	case p.memory.capacity != 0:
+		return nil, fmt.Errorf("%w: memory can't be modelled live", ErrInvalidArgs)
+	case speed < 0 || math.IsInf(speed, 0) || math.IsNaN(speed):
+		return nil, fmt.Errorf("%w: speed must be a positive multiple of real time, or 0, got %g", ErrInvalidArgs, speed)
+	case speed > 0 && p.time.tickDuration() <= 0:
+		return nil, fmt.Errorf("%w: unitless times need a -tick to run in real time", ErrInvalidArgs)
+	}
+	if err := p.cpu.validate(nil); err != nil {
+		return nil, err
+	}
+
+	return &LiveSession{
+		pol:     pol,
+		p:       p,
+		speed:   speed,
+		notify:  make(chan struct{}, 1),
+		ids:     make(map[int64]bool),
+		blocked: make(map[int64]int64),
+		running: -1,
+	}, nil
+}
+
+// Title returns the title of the session's policy.
+func (s *LiveSession) Title() string {
+	return s.pol.title
+}
+
+// Submit submits a process as a CSV record, as in a scheduling file; the first record may instead be a header
+// naming the columns of the records after it. It's safe to call while the session runs.
+func (s *LiveSession) Submit(record string) error {
+	row, err := csv.NewReader(strings.NewReader(record)).Read()
+	if err != nil {
+		return fmt.Errorf("%w: reading CSV: %v", ErrInvalidProcesses, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("%w: the session is closed", ErrInvalidArgs)
+	}
+	if s.record == 0 && s.columns == nil && len(row) > 0 {
+		if _, err := strconv.ParseInt(strings.TrimSpace(row[0]), 10, 64); err != nil {
+			s.columns, err = csvHeader(row)
+			return err
+		}
+	}
+
+	var p Process
+	if err := parseRecord(&p, row, s.columns, s.p.time, s.record+1); err != nil {
+		return err
+	}
+	if err := validateProcess(p, s.record+1); err != nil {
+		return err
+	}
+	switch {
+	case len(p.DependsOn) > 0:
+		return fmt.Errorf("%w: record %d: process %d depends on other processes, which can't run live",
+			ErrInvalidProcesses, s.record+1, p.ProcessID)
+	case s.ids[p.ProcessID]:
+		return fmt.Errorf("%w: record %d: duplicate process ID %d", ErrInvalidProcesses, s.record+1, p.ProcessID)
+	}
+	// Every process must be able to finish at the slowest speed without the clock overflowing
+	var (
+		work  = s.p.cpu.stretch(p.BurstDuration, int(maxi(0, int64(len(s.p.cpu.states)-1))))
+		start = maxi(s.end, p.ArrivalTime)
+	)
+	if work < 0 || work > math.MaxInt64/2-start {
+		return fmt.Errorf("%w: record %d: schedule would run past the largest representable time", ErrInvalidProcesses, s.record+1)
+	}
+	s.end = start + work
+	s.record++
+	s.ids[p.ProcessID] = true
+	s.incoming = append(s.incoming, submission{process: p, at: time.Now()})
+	s.wake()
+
+	return nil
+}
+
+// Close ends the submissions, after which the session runs until the processes submitted complete.
+func (s *LiveSession) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.wake()
+}
+
+func (s *LiveSession) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs the session until it's closed and its processes complete, writing each event to w as it happens,
+// with the processes ready to run as each slice starts, followed by the GANTT chart so far, and returns the
+// schedule.
+func (s *LiveSession) Run(w io.Writer) Result {
+	var (
+		ts  = s.p.time
+		res = Result{
+			Gantt:    make([]TimeSlice, 0),
+			Stats:    make([]ProcessStats, 0),
+			CPU:      s.p.cpu,
+			Time:     ts,
+			Chart:    s.p.chart,
+			Timeline: s.p.timeline,
+			TieBreak: s.p.tieBreak,
+		}
+	)
+	s.log, s.start = w, time.Now()
+	s.pol.stream(s.p).simulate(s, 1, s.p.cpu,
+		func(slice TimeSlice) {
+			res.Gantt = append(res.Gantt, slice)
+			s.running = slice.PID
+			_, _ = fmt.Fprintf(w, "%s: %d runs until %s | ready: %s\n",
+				ts.format(slice.Start), slice.PID, ts.format(slice.Stop), s.ready(slice.Start))
+			outputGantt(w, withIdle(res.Gantt), ts, s.p.chart)
+		},
+		func(io TimeSlice) {
+			res.IO = append(res.IO, io)
+			s.running = -1
+			s.blocked[io.PID] = io.Stop
+			_, _ = fmt.Fprintf(w, "%s: %d blocks on I/O until %s\n", ts.format(io.Start), io.PID, ts.format(io.Stop))
+		},
+		func(_ int, stats ProcessStats) {
+			res.Stats = append(res.Stats, stats)
+			_, _ = fmt.Fprintf(w, "%s: %d exits, having waited %s\n", ts.format(stats.Exit), stats.ProcessID, ts.format(stats.Wait))
+		})
+
+	// Time the CPU waited for processes to arrive is idle, and processes are tabled in arrival order
+	res.Gantt = withIdle(res.Gantt)
+	sort.SliceStable(res.Stats, func(i, j int) bool { return res.Stats[i].ArrivalTime < res.Stats[j].ArrivalTime })
+
+	return res
+}
+
+// ready returns the IDs of the processes ready to run at a time, besides the one running, in arrival order.
+func (s *LiveSession) ready(at int64) string {
+	var ready []*Process
+	for i := range s.slots {
+		p := &s.slots[i]
+		if p.BurstDuration > 0 && p.ProcessID != s.running && s.blocked[p.ProcessID] <= at {
+			ready = append(ready, p)
+		}
+	}
+	if len(ready) == 0 {
+		return "none"
+	}
+	// Slots are reused, so they aren't in arrival order
+	sort.SliceStable(ready, func(i, j int) bool { return ready[i].ArrivalTime < ready[j].ArrivalTime })
+	ids := make([]string, len(ready))
+	for i, p := range ready {
+		ids[i] = strconv.FormatInt(p.ProcessID, 10)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// ticks returns the ticks of the clock at a time, which is zero at speed 0.
+func (s *LiveSession) ticks(at time.Time) int64 {
+	if s.speed == 0 {
+		return 0
+	}
+	ticks := float64(at.Sub(s.start)) * s.speed / float64(s.p.time.tickDuration())
+	return int64(math.Min(ticks, math.MaxInt64/2))
+}
+
+// until returns how long until the clock reaches a number of ticks.
+func (s *LiveSession) until(ticks int64) time.Duration {
+	if s.speed == 0 {
+		return 0
+	}
+	d := float64(ticks) * float64(s.p.time.tickDuration()) / s.speed
+	return time.Duration(math.Min(d, math.MaxInt64/2)) - time.Since(s.start)
+}
+
+// take moves the processes submitted since it was last called to those pending, each arriving no sooner
+// than now, and reports whether the session is closed.
+func (s *LiveSession) take(now int64) bool {
+	s.mu.Lock()
+	incoming, closed := s.incoming, s.closed
+	s.incoming = nil
+	s.mu.Unlock()
+
+	for _, sub := range incoming {
+		p := sub.process
+		p.ArrivalTime = maxi(p.ArrivalTime, maxi(now, s.ticks(sub.at)))
+		s.pending = append(s.pending, p)
+	}
+	sort.SliceStable(s.pending, func(i, j int) bool { return s.pending[i].ArrivalTime < s.pending[j].ArrivalTime })
+
+	return closed
+}
+
+func (s *LiveSession) wait(now, next int64) int64 {
+	for {
+		closed := s.take(now)
+		due := next
+		if len(s.pending) > 0 && (due < 0 || s.pending[0].ArrivalTime < due) {
+			due = s.pending[0].ArrivalTime
+		}
+		if due < 0 && closed {
+			return -1
+		}
+
+		var (
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		if due >= 0 {
+			d := s.until(due)
+			if d <= 0 {
+				return due
+			}
+			timer = time.NewTimer(d)
+			fire = timer.C
+		}
+		select {
+		case <-s.notify:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+			return due
+		}
+	}
+}
+
+// admit returns the slots of the processes that have arrived by now.
+func (s *LiveSession) admit(now int64) []int {
+	admitted := make([]int, 0)
+	for len(s.pending) > 0 && s.pending[0].ArrivalTime <= now {
+		p := s.pending[0]
+		s.pending = s.pending[1:]
+		i := len(s.slots)
+		if n := len(s.free); n > 0 {
+			i, s.free = s.free[n-1], s.free[:n-1]
+			s.slots[i] = p
+		} else {
+			s.slots = append(s.slots, p)
+		}
+		admitted = append(admitted, i)
+		_, _ = fmt.Fprintf(s.log, "%s: %d arrives to run for %s\n",
+			s.p.time.format(now), p.ProcessID, s.p.time.format(p.BurstDuration))
+	}
+
+	return admitted
+}
+
+// complete frees the slot of a completed process, and its ID to be submitted again.
+func (s *LiveSession) complete(i int, _ int64) {
+	s.mu.Lock()
+	delete(s.ids, s.slots[i].ProcessID)
+	s.mu.Unlock()
+	if s.running == s.slots[i].ProcessID {
+		s.running = -1
+	}
+	delete(s.blocked, s.slots[i].ProcessID)
+	s.slots[i] = Process{}
+	s.free = append(s.free, i)
+}
+
+func (s *LiveSession) arriving() bool {
+	return len(s.pending) > 0
+}
+
+func (s *LiveSession) nextArrival() int64 {
+	return s.pending[0].ArrivalTime
+}
+
+func (s *LiveSession) process(i int) *Process {
+	return &s.slots[i]
+}
+
+// delay is always zero, as live processes are admitted as they arrive.
+func (s *LiveSession) delay(int) int64 {
+	return 0
+}
@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewLiveSession(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		policy  string
+		params  map[string]string
+		speed   float64
+		wantErr error
+	}{
+		{name: "success", policy: "rr", speed: 1},
+		{name: "as fast as possible", policy: "sjf"},
+		{name: "unknown policy", policy: "lottery", wantErr: ErrInvalidArgs},
+		{name: "policy that can't run live", policy: "gang", wantErr: ErrInvalidArgs},
+		{name: "memory", policy: "rr", params: map[string]string{"memory": "100"}, wantErr: ErrInvalidArgs},
+		{name: "negative speed", policy: "rr", speed: -1, wantErr: ErrInvalidArgs},
+		{name: "unitless without a tick", policy: "rr", params: map[string]string{"tick": "0s"}, speed: 1, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			for name, value := range tt.params {
+				if err := p.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := NewLiveSession(tt.policy, p, tt.speed); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewLiveSession() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLiveSession_Submit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		records []string
+		wantErr error
+	}{
+		{name: "records", records: []string{"1,5,0", "2,3,4,1"}},
+		{name: "header", records: []string{"id,burst,arrival,priority", "1,5,0,2"}},
+		{name: "bad header", records: []string{"id,burst,when"}, wantErr: ErrInvalidProcesses},
+		{name: "bad record", records: []string{"1,five,0"}, wantErr: ErrInvalidProcesses},
+		{name: "zero burst", records: []string{"1,0,0"}, wantErr: ErrInvalidProcesses},
+		{name: "duplicate", records: []string{"1,5,0", "1,3,0"}, wantErr: ErrInvalidProcesses},
+		{name: "dependencies", records: []string{"id,burst,arrival,depends", "2,3,0,1"}, wantErr: ErrInvalidProcesses},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := NewLiveSession("rr", DefaultParams(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, record := range tt.records {
+				if err = s.Submit(record); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Submit() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+		s, err := NewLiveSession("rr", DefaultParams(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+		if err := s.Submit("1,5,0"); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("Submit() error = %v, want %v", err, ErrInvalidArgs)
+		}
+	})
+}
+
+// lineWriter passes each line written to it to a channel.
+type lineWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	lines chan string
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(b)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			w.buf.WriteString(line)
+			return len(b), nil
+		}
+		w.lines <- strings.TrimSuffix(line, "\n")
+	}
+}
+
+func TestLiveSession_Run(t *testing.T) {
+	t.Parallel()
+	s, err := NewLiveSession("rr", DefaultParams(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []string{"1,3,0", "2,2,1"} {
+		if err := s.Submit(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Process 3 is submitted once the others have exited and the CPU is idle
+	w := &lineWriter{lines: make(chan string, 100)}
+	done := make(chan Result)
+	go func() { done <- s.Run(w) }()
+	var lines []string
+	for line := range w.lines {
+		lines = append(lines, line)
+		if line == "5: 1 exits, having waited 2" {
+			break
+		}
+	}
+	if err := s.Submit("3,1,0"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	res := <-done
+	close(w.lines)
+	for line := range w.lines {
+		lines = append(lines, line)
+	}
+
+	// Each slice is followed by the GANTT chart so far, of which the times on its axis are checked
+	var (
+		events []string
+		axes   [][]string
+	)
+	for i, line := range lines {
+		switch {
+		case line == "Gantt schedule" && i+2 < len(lines):
+			axes = append(axes, strings.Fields(lines[i+2]))
+		case strings.Contains(line, ": ") && !strings.HasPrefix(line, "Legend"):
+			events = append(events, line)
+		}
+	}
+	wantLines := []string{
+		"0: 1 arrives to run for 3",
+		"0: 1 runs until 2 | ready: none",
+		"1: 2 arrives to run for 2",
+		"2: 2 runs until 4 | ready: 1",
+		"4: 2 exits, having waited 1",
+		"4: 1 runs until 5 | ready: none",
+		"5: 1 exits, having waited 2",
+		"5: 3 arrives to run for 1",
+		"5: 3 runs until 6 | ready: none",
+		"6: 3 exits, having waited 0",
+	}
+	if !reflect.DeepEqual(events, wantLines) {
+		t.Errorf("Run() wrote %q, want %q", events, wantLines)
+	}
+	wantAxes := [][]string{{"0", "2"}, {"0", "2", "4"}, {"0", "2", "4", "5"}, {"0", "2", "4", "5", "6"}}
+	if !reflect.DeepEqual(axes, wantAxes) {
+		t.Errorf("Run() charted times %q, want %q", axes, wantAxes)
+	}
+	wantGantt := []TimeSlice{{PID: 1, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 5}, {PID: 3, Start: 5, Stop: 6}}
+	if !reflect.DeepEqual(res.Gantt, wantGantt) {
+		t.Errorf("Run() Gantt = %v, want %v", res.Gantt, wantGantt)
+	}
+	if got := res.Stats[2]; got.ProcessID != 3 || got.ArrivalTime != 5 || got.Turnaround != 1 {
+		t.Errorf("Run() timing of process 3 = %+v, want it to arrive at 5 and turn around in 1", got)
+	}
+}
+
+func TestLiveSession_Run_readyOrder(t *testing.T) {
+	t.Parallel()
+	s, err := NewLiveSession("rr", DefaultParams(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Process 3 takes the slot process 1 exits from, ahead of process 2's
+	for _, record := range []string{"1,1,0", "2,4,0", "4,4,0", "3,4,2"} {
+		if err := s.Submit(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var w bytes.Buffer
+	s.Run(&w)
+	if want := "3: 4 runs until 5 | ready: 2, 3\n"; !strings.Contains(w.String(), want) {
+		t.Errorf("Run() wrote %s, want it to contain %q", w.String(), want)
+	}
+}
+
+func TestLiveSession_Run_realTime(t *testing.T) {
+	t.Parallel()
+	p := DefaultParams()
+	if err := p.Set("unit", "ms"); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewLiveSession("sjf", p, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Submit("1,20,0"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	start := time.Now()
+	var w bytes.Buffer
+	res := s.Run(&w)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Run() took %v, want at least the 20ms the process runs for", elapsed)
+	}
+	if len(res.Stats) != 1 || res.Stats[0].Exit != 20 {
+		t.Errorf("Run() timing = %+v, want the process to exit at 20", res.Stats)
+	}
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reportFormats output a titled schedule in each format a report can be written in, by name.
+var reportFormats = map[string]func(w io.Writer, title string, res Result){
+	"text":     outputResult,
+	"markdown": outputMarkdown,
+	"latex":    outputLaTeX,
+}
+
+// RenderFormat outputs the schedule in a report format: "text", as Render does, or "markdown" or "latex", which
+// output the same report for inclusion in documents.
+func (r Result) RenderFormat(w io.Writer, title, format string) error {
+	render, ok := reportFormats[format]
+	if !ok {
+		return fmt.Errorf("%w: unknown report format %q", ErrInvalidArgs, format)
+	}
+	render(w, title, r)
+	return nil
+}
+
+// flatten joins the lines of a table cell with spaces, for formats whose cells are one line.
+func flatten(cell string) string {
+	return strings.ReplaceAll(cell, "\n", " ")
+}
+
+//region Markdown
+
+// markdownEscaper escapes the characters that would end a Markdown table cell.
+var markdownEscaper = strings.NewReplacer(`|`, `\|`)
+
+// outputMarkdown outputs a schedule as a Markdown section: a Mermaid GANTT chart with a section for each CPU,
+// the table of timing with its footer in bold, and the rest of the report's sections as subsections.
+func outputMarkdown(w io.Writer, title string, res Result) {
+	_, _ = fmt.Fprintf(w, "## %s\n\n", title)
+	if len(res.TieBreak) > 0 {
+		_, _ = fmt.Fprintf(w, "%s.\n\n", res.TieBreak.header())
+	}
+
+	// Times are plotted as seconds since the epoch, so the axis shows them as they are
+	_, _ = io.WriteString(w, "```mermaid\ngantt\n    dateFormat X\n    axisFormat %s\n")
+	rows := ganttRows(res.Gantt)
+	for cpu, row := range rows {
+		if len(rows) > 1 {
+			_, _ = fmt.Fprintf(w, "    section CPU %d\n", cpu)
+		} else {
+			_, _ = fmt.Fprintln(w, "    section CPU")
+		}
+		for _, segment := range row {
+			if !segment.idle {
+				_, _ = fmt.Fprintf(w, "    %d : %s, %s\n", segment.pid, res.Time.format(segment.start), res.Time.format(segment.stop))
+			}
+		}
+	}
+	_, _ = fmt.Fprint(w, "```\n\n")
+
+	outputMarkdownTable(w, res.table())
+	for _, s := range res.sections() {
+		if s.title != "" {
+			_, _ = fmt.Fprintf(w, "### %s\n\n", s.title)
+		}
+		if s.table != nil {
+			outputMarkdownTable(w, *s.table)
+		}
+		for _, line := range s.lines {
+			_, _ = fmt.Fprintf(w, "%s\n\n", line)
+		}
+	}
+}
+
+// outputMarkdownTable outputs a table with right-aligned columns and its footer, if it has one, in bold.
+func outputMarkdownTable(w io.Writer, t scheduleTable) {
+	row := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = markdownEscaper.Replace(cell)
+		}
+		_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+	}
+	row(t.header)
+	align := make([]string, len(t.header))
+	for i := range align {
+		align[i] = "---:"
+	}
+	row(align)
+	for _, r := range t.rows {
+		row(r)
+	}
+	if t.footer != nil {
+		footer := make([]string, len(t.footer))
+		for i, cell := range t.footer {
+			if cell != "" {
+				footer[i] = "**" + flatten(cell) + "**"
+			}
+		}
+		row(footer)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+//endregion
+
+//region LaTeX
+
+// latexColours fill the slices of processes in a TikZ GANTT chart, by their ID, in the order of ganttColours.
+var latexColours = []string{"red!30", "green!30", "yellow!40", "blue!30", "magenta!30", "cyan!30"}
+
+// latexEscaper escapes the characters LaTeX treats specially.
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`,
+	`{`, `\{`, `}`, `\}`, `~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	`<`, `\textless{}`, `>`, `\textgreater{}`, `|`, `\textbar{}`, `→`, `$\rightarrow$`,
+)
+
+// latexChartWidth is how many centimetres wide a TikZ GANTT chart is drawn.
+const latexChartWidth = 12.0
+
+// outputLaTeX outputs a schedule as a LaTeX section: a TikZ GANTT chart with a row for each CPU and dashed idle
+// time, the table of timing as a tabular, and the rest of the report's sections as subsections.
+func outputLaTeX(w io.Writer, title string, res Result) {
+	ts := res.Time
+	_, _ = fmt.Fprintf(w, "\\subsection*{%s}\n\n", latexEscaper.Replace(title))
+	if len(res.TieBreak) > 0 {
+		_, _ = fmt.Fprintf(w, "%s.\n\n", latexEscaper.Replace(res.TieBreak.header()))
+	}
+
+	rows := ganttRows(res.Gantt)
+	var end int64
+	for _, row := range rows {
+		if len(row) > 0 {
+			end = maxi(end, row[len(row)-1].stop)
+		}
+	}
+	scale := latexChartWidth
+	if end > 0 {
+		scale /= float64(end) / float64(ts.perUnit())
+	}
+	_, _ = fmt.Fprintf(w, "\\begin{tikzpicture}[x=%scm, y=-0.8cm]\n", strconv.FormatFloat(scale, 'f', 4, 64))
+	ticks := map[int64]bool{0: true}
+	for cpu, row := range rows {
+		if len(rows) > 1 {
+			_, _ = fmt.Fprintf(w, "  \\node[left] at (0,%d.5) {CPU %d};\n", cpu, cpu)
+		}
+		for _, segment := range row {
+			from, to := fmt.Sprintf("(%s,%d)", ts.format(segment.start), cpu), fmt.Sprintf("(%s,%d)", ts.format(segment.stop), cpu+1)
+			if segment.idle {
+				_, _ = fmt.Fprintf(w, "  \\draw[dashed] %s rectangle %s;\n", from, to)
+			} else {
+				_, _ = fmt.Fprintf(w, "  \\draw[fill=%s] %s rectangle %s node[pos=.5] {%d};\n",
+					latexColours[(segment.pid%6+6)%6], from, to, segment.pid)
+			}
+			ticks[segment.start], ticks[segment.stop] = true, true
+		}
+	}
+	times := make([]int64, 0, len(ticks))
+	for t := range ticks {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, t := range times {
+		_, _ = fmt.Fprintf(w, "  \\node[below, font=\\scriptsize] at (%s,%d) {%s};\n", ts.format(t), len(rows), ts.format(t))
+	}
+	_, _ = fmt.Fprint(w, "\\end{tikzpicture}\n\n")
+
+	outputLaTeXTable(w, res.table())
+	for _, s := range res.sections() {
+		if s.title != "" {
+			_, _ = fmt.Fprintf(w, "\\subsubsection*{%s}\n\n", latexEscaper.Replace(s.title))
+		}
+		if s.table != nil {
+			outputLaTeXTable(w, *s.table)
+		}
+		for _, line := range s.lines {
+			_, _ = fmt.Fprintf(w, "%s\n\n", latexEscaper.Replace(line))
+		}
+	}
+}
+
+// outputLaTeXTable outputs a table as a tabular with right-aligned columns, ruling off its footer if it has one.
+func outputLaTeXTable(w io.Writer, t scheduleTable) {
+	row := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = latexEscaper.Replace(flatten(cell))
+		}
+		_, _ = fmt.Fprintf(w, "%s \\\\\n", strings.Join(escaped, " & "))
+	}
+	_, _ = fmt.Fprintf(w, "\\begin{tabular}{%s}\n\\hline\n", strings.Repeat("r", len(t.header)))
+	row(t.header)
+	_, _ = fmt.Fprintln(w, "\\hline")
+	for _, r := range t.rows {
+		row(r)
+	}
+	_, _ = fmt.Fprintln(w, "\\hline")
+	if t.footer != nil {
+		row(t.footer)
+		_, _ = fmt.Fprintln(w, "\\hline")
+	}
+	_, _ = fmt.Fprint(w, "\\end{tabular}\n\n")
+}
+
+//endregion
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResult_RenderFormat(t *testing.T) {
+	t.Parallel()
+	res := fcfs([]Process{
+		{ProcessID: 1, BurstDuration: 2, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 1, Priority: 2},
+	}, memoryConfig{}, cpuModel{}, nil)
+	tests := []struct {
+		name     string
+		title    string
+		format   string
+		contains []string
+		wantErr  error
+	}{
+		{
+			name:   "markdown",
+			title:  "FCFS",
+			format: "markdown",
+			contains: []string{
+				"## FCFS\n",
+				"```mermaid\ngantt\n    dateFormat X\n    axisFormat %s\n    section CPU\n    1 : 0, 2\n    2 : 3, 4\n```\n",
+				"| ID | Priority | Burst | Arrival | Wait | Turnaround | Exit |\n| ---: |",
+				"| 2 | 2 | 1 | 3 | 0 | 1 | 4 |\n",
+				"| **CPU idle 1** |",
+			},
+		},
+		{
+			name:   "latex escapes the title",
+			title:  "FCFS & 100% of #1",
+			format: "latex",
+			contains: []string{
+				`\subsection*{FCFS \& 100\% of \#1}`,
+				`\begin{tikzpicture}[x=3.0000cm, y=-0.8cm]`,
+				`\draw[fill=green!30] (0,0) rectangle (2,1) node[pos=.5] {1};`,
+				`\draw[dashed] (2,0) rectangle (3,1);`,
+				`\node[below, font=\scriptsize] at (4,1) {4};`,
+				"\\begin{tabular}{rrrrrrr}\n\\hline\nID & Priority & Burst & Arrival & Wait & Turnaround & Exit \\\\\n",
+				"1 & 1 & 2 & 0 & 0 & 2 & 2 \\\\\n",
+			},
+		},
+		{name: "unknown", format: "html", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := res.RenderFormat(&w, tt.title, tt.format); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RenderFormat() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(w.String(), want) {
+					t.Errorf("RenderFormat() = %s, want it to contain %s", w.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestResult_RenderFormat_escapes(t *testing.T) {
+	t.Parallel()
+	res := fcfs([]Process{
+		{ProcessID: 1, BurstDuration: 2, Owner: "r&d|ops_1"},
+		{ProcessID: 2, BurstDuration: 1, Owner: "bob"},
+	}, memoryConfig{}, cpuModel{}, nil)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "markdown", want: `| 1 | r&d\|ops_1 | 100.0% |`},
+		{format: "latex", want: `1 & r\&d\textbar{}ops\_1 & 100.0\% &`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := res.RenderFormat(&w, "FCFS", tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(w.String(), tt.want) {
+				t.Errorf("RenderFormat() = %s, want it to contain %s", w.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrPlugin is returned when the program of a plugin policy fails, or breaks the plugin protocol.
+var ErrPlugin = errors.New("plugin failed")
+
+// pluginTimeout bounds how long a plugin may take to answer, and to exit once the schedule ends.
+const pluginTimeout = 10 * time.Second
+
+type (
+	// pluginMessage is a line of JSON sent to a plugin: "start" before the schedule, with the unit of its
+	// times; "decide" each time the CPU is free, with the time and the ready processes in the order they became
+	// ready; and "end" once every process has completed. The plugin answers each decide with a pluginChoice.
+	pluginMessage struct {
+		Type  string          `json:"type"`
+		Unit  string          `json:"unit,omitempty"`
+		Time  json.Number     `json:"time,omitempty"`
+		Ready []pluginProcess `json:"ready,omitempty"`
+	}
+	// pluginProcess is a ready process, with the work it has left.
+	pluginProcess struct {
+		PID       int64       `json:"pid"`
+		Priority  int64       `json:"priority"`
+		Burst     json.Number `json:"burst"`
+		Arrival   json.Number `json:"arrival"`
+		Remaining json.Number `json:"remaining"`
+	}
+	// pluginChoice is a line of JSON a plugin answers a decide message with: the ready process to run, and
+	// how long to run it for; a slice that's omitted, zero or longer than the work it has left runs it until it
+	// completes.
+	pluginChoice struct {
+		PID   int64       `json:"pid"`
+		Slice json.Number `json:"slice"`
+	}
+)
+
+// plugin is a running plugin program, which decides what the plugin policy runs.
+type plugin struct {
+	cmd   *exec.Cmd
+	in    io.WriteCloser
+	lines chan string
+	// stderr is what the program has written to stderr, to report once it has exited if it failed.
+	stderr bytes.Buffer
+	scale  timeScale
+	// err is the first time the program failed, after which it's no longer asked.
+	err error
+}
+
+// startPlugin starts the program of a plugin by its command line, e.g. "python3 policy.py".
+func startPlugin(command string, scale timeScale) (*plugin, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: the plugin policy needs a -plugin program to run", ErrInvalidArgs)
+	}
+	pl := &plugin{cmd: exec.Command(args[0], args[1:]...), lines: make(chan string), scale: scale}
+	pl.cmd.Stderr = &pl.stderr
+	in, err := pl.cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlugin, err)
+	}
+	out, err := pl.cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlugin, err)
+	}
+	if err := pl.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlugin, err)
+	}
+	pl.in = in
+	go func() {
+		defer close(pl.lines)
+		lines := bufio.NewScanner(out)
+		for lines.Scan() {
+			pl.lines <- lines.Text()
+		}
+	}()
+
+	return pl, nil
+}
+
+// send writes a message to the program.
+func (pl *plugin) send(msg pluginMessage) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := pl.in.Write(append(line, '\n')); err != nil {
+		return pl.failed(err)
+	}
+	return nil
+}
+
+// receive reads the program's answer to a decide message.
+func (pl *plugin) receive() (pluginChoice, error) {
+	var choice pluginChoice
+	timeout := time.NewTimer(pluginTimeout)
+	defer timeout.Stop()
+	select {
+	case line, ok := <-pl.lines:
+		if !ok {
+			return choice, pl.failed(errors.New("exited without answering"))
+		}
+		if err := json.Unmarshal([]byte(line), &choice); err != nil {
+			return choice, fmt.Errorf("%w: answered %q: %v", ErrPlugin, line, err)
+		}
+		return choice, nil
+	case <-timeout.C:
+		return choice, fmt.Errorf("%w: didn't answer within %v", ErrPlugin, pluginTimeout)
+	}
+}
+
+// failed returns an error for the program failing.
+func (pl *plugin) failed(err error) error {
+	return fmt.Errorf("%w: %v", ErrPlugin, err)
+}
+
+// close ends the schedule and waits for the program to exit, killing it if it has failed or doesn't exit.
+func (pl *plugin) close() {
+	if pl.err == nil {
+		_ = pl.send(pluginMessage{Type: "end"})
+	}
+	_ = pl.in.Close()
+	exited := make(chan struct{})
+	go func() {
+		// Drain the program's output so it can exit
+		for range pl.lines {
+		}
+		_ = pl.cmd.Wait()
+		close(exited)
+	}()
+	if pl.err != nil {
+		_ = pl.cmd.Process.Kill()
+	}
+	select {
+	case <-exited:
+	case <-time.After(pluginTimeout):
+		_ = pl.cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// pluginQueue is a ready queue, in the order processes became ready, that asks the plugin which process to
+// pop and for how long to run it.
+type pluginQueue struct {
+	*plugin
+	src       arrivals
+	remaining func(i int) int64
+	now       func() int64
+	queue     []int
+	// slice is the work the plugin chose to run the process popped last for.
+	slice int64
+}
+
+func (q *pluginQueue) push(i int) {
+	q.queue = append(q.queue, i)
+}
+
+func (q *pluginQueue) pop() int {
+	var n int
+	if q.err == nil {
+		n, q.slice, q.err = q.decide()
+	}
+	// Once the plugin has failed, the schedule is only finished to be discarded
+	if q.err != nil {
+		n, q.slice = 0, q.remaining(q.queue[0])
+	}
+	i := q.queue[n]
+	q.queue = append(q.queue[:n], q.queue[n+1:]...)
+	return i
+}
+
+func (q *pluginQueue) len() int {
+	return len(q.queue)
+}
+
+func (q *pluginQueue) each(f func(i int)) {
+	for _, i := range q.queue {
+		f(i)
+	}
+}
+
+// decide asks the plugin which ready process to run now, returning its position in the queue and the work to
+// run it for, or an error if the plugin's choice can't be run.
+func (q *pluginQueue) decide() (int, int64, error) {
+	var (
+		ts  = q.scale
+		now = q.now()
+		msg = pluginMessage{Type: "decide", Time: json.Number(ts.format(now)), Ready: make([]pluginProcess, len(q.queue))}
+	)
+	for n, i := range q.queue {
+		p := q.src.process(i)
+		msg.Ready[n] = pluginProcess{
+			PID:       p.ProcessID,
+			Priority:  p.Priority,
+			Burst:     json.Number(ts.format(p.BurstDuration)),
+			Arrival:   json.Number(ts.format(p.ArrivalTime)),
+			Remaining: json.Number(ts.format(q.remaining(i))),
+		}
+	}
+	if err := q.send(msg); err != nil {
+		return 0, 0, err
+	}
+	choice, err := q.receive()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for n, i := range q.queue {
+		if q.src.process(i).ProcessID != choice.PID {
+			continue
+		}
+		remaining := q.remaining(i)
+		if choice.Slice == "" {
+			return n, remaining, nil
+		}
+		slice, err := ts.parse(string(choice.Slice))
+		switch {
+		case err != nil:
+			return 0, 0, fmt.Errorf("%w: at %s: slice: %v", ErrPlugin, ts.format(now), err)
+		case slice < 0:
+			return 0, 0, fmt.Errorf("%w: at %s: chose a negative slice of %s for process %d",
+				ErrPlugin, ts.format(now), choice.Slice, choice.PID)
+		case slice == 0 || slice > remaining:
+			slice = remaining
+		}
+		return n, slice, nil
+	}
+	return 0, 0, fmt.Errorf("%w: at %s: chose process %d, which isn't ready", ErrPlugin, ts.format(now), choice.PID)
+}
+
+// runPlugin schedules processes by the plugin program named by the -plugin parameter, which decides which ready
+// process to run each time the CPU is free and for how long, blocking processes on I/O as round-robin does.
+// The schedule is recorded like any other, and an error wrapping ErrPlugin is returned if the program fails
+// or makes a choice that can't be run.
+func runPlugin(processes []Process, p Params) (Result, error) {
+	pl, err := startPlugin(p.plugin, p.time)
+	if err != nil {
+		return Result{}, err
+	}
+	pl.err = pl.send(pluginMessage{Type: "start", Unit: p.time.unit})
+
+	var q *pluginQueue
+	res := discipline{
+		queue: func(src arrivals, remaining func(i int) int64, now func() int64) readyQueue {
+			q = &pluginQueue{plugin: pl, src: src, remaining: remaining, now: now}
+			return q
+		},
+		run: func(*Process, int64, int) (int64, int) {
+			return q.slice, 0
+		},
+		io:   p.io,
+		ties: p.tieBreak,
+	}.schedule(processes, p.memory, cpuModel{})
+	pl.close()
+	if pl.err != nil {
+		if stderr := strings.TrimSpace(pl.stderr.String()); stderr != "" {
+			return Result{}, fmt.Errorf("%w: %s", pl.err, stderr)
+		}
+		return Result{}, pl.err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestPluginProcess is the program the plugin tests run, which chooses by the mode after -- on its command line.
+func TestPluginProcess(t *testing.T) {
+	if len(flag.Args()) != 1 {
+		return
+	}
+	mode := flag.Args()[0]
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var msg pluginMessage
+		if err := json.Unmarshal(in.Bytes(), &msg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if msg.Type != "decide" {
+			continue
+		}
+		first, last := msg.Ready[0], msg.Ready[len(msg.Ready)-1]
+		switch mode {
+		case "first":
+			fmt.Printf(`{"pid": %d}`+"\n", first.PID)
+		case "rr":
+			fmt.Printf(`{"pid": %d, "slice": 2}`+"\n", first.PID)
+		case "last":
+			fmt.Printf(`{"pid": %d, "slice": 0}`+"\n", last.PID)
+		case "missing":
+			fmt.Println(`{"pid": 999}`)
+		case "negative":
+			fmt.Printf(`{"pid": %d, "slice": -1}`+"\n", first.PID)
+		case "garbage":
+			fmt.Println("run them all")
+		case "exit":
+			fmt.Fprintln(os.Stderr, "out of ideas")
+			os.Exit(1)
+		}
+	}
+	os.Exit(0)
+}
+
+func Test_runPlugin(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1},
+	}
+	// Every process arrives together, in neither the order of their IDs nor of their priorities
+	together := []Process{
+		{ProcessID: 3, BurstDuration: 2, Priority: 2},
+		{ProcessID: 1, BurstDuration: 1, Priority: 3},
+		{ProcessID: 2, BurstDuration: 3, Priority: 1},
+	}
+	tests := []struct {
+		name      string
+		mode      string
+		processes []Process
+		tieBreak  string
+		like      string
+		wantGantt []TimeSlice
+		wantErr   error
+	}{
+		{name: "first ready is FCFS", mode: "first", like: "fcfs"},
+		{name: "ready by ID", mode: "first", processes: together, tieBreak: "pid", like: "fcfs"},
+		{name: "ready by priority", mode: "first", processes: together, tieBreak: "priority", like: "fcfs"},
+		{name: "ready in file order", mode: "first", processes: together, tieBreak: "", like: "fcfs"},
+		{name: "first ready for a quantum is round-robin", mode: "rr", like: "rr"},
+		{
+			name:      "last ready",
+			mode:      "last",
+			wantGantt: []TimeSlice{{PID: 1, Stop: 5}, {PID: 3, Start: 5, Stop: 6}, {PID: 2, Start: 6, Stop: 9}},
+		},
+		{name: "process that isn't ready", mode: "missing", wantErr: ErrPlugin},
+		{name: "negative slice", mode: "negative", wantErr: ErrPlugin},
+		{name: "answer that isn't JSON", mode: "garbage", wantErr: ErrPlugin},
+		{name: "program exits", mode: "exit", wantErr: ErrPlugin},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pol, err := LookupPolicy("plugin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := DefaultParams()
+			if err := p.Set("plugin", fmt.Sprintf("%s -test.run=^TestPluginProcess$ -- %s", os.Args[0], tt.mode)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.processes == nil {
+				tt.processes = processes
+			} else if err := p.Set("tie-break", tt.tieBreak); err != nil {
+				t.Fatal(err)
+			}
+			processes := tt.processes
+			res, err := pol.Schedule(processes, p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Schedule() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := pol.Verify(processes, res); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+			want := tt.wantGantt
+			if tt.like != "" {
+				like, err := LookupPolicy(tt.like)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want = like.schedule(processes, p).Gantt
+			}
+			if !reflect.DeepEqual(res.Gantt, want) {
+				t.Errorf("Schedule() Gantt = %v, want %v", res.Gantt, want)
+			}
+		})
+	}
+
+	t.Run("no program", func(t *testing.T) {
+		t.Parallel()
+		if _, err := pluginPolicy.Schedule(processes, DefaultParams()); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("Schedule() error = %v, want %v", err, ErrInvalidArgs)
+		}
+	})
+}
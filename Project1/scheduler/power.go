@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pstate is a frequency/power state of the CPU.
+type pstate struct {
+	// speed is the CPU's frequency as a percentage of full speed; bursts are measured at full speed.
+	speed int64
+	// power is the power the CPU draws while running at this speed.
+	power float64
+}
+
+// cpuModel is the power model of each CPU: the frequency states it can run processes at, fastest first,
+// and the power it draws while idle.
+type cpuModel struct {
+	states    []pstate
+	idlePower float64
+}
+
+// String formats the frequency states as the -pstates flag, e.g. "100:8,50:2".
+func (m *cpuModel) String() string {
+	states := make([]string, len(m.states))
+	for i, s := range m.states {
+		states[i] = fmt.Sprintf("%d:%s", s.speed, strconv.FormatFloat(s.power, 'f', -1, 64))
+	}
+	return strings.Join(states, ",")
+}
+
+// Set parses the frequency states from the -pstates flag, a list of speed:power pairs.
+func (m *cpuModel) Set(s string) error {
+	var states []pstate
+	for _, field := range strings.Split(s, ",") {
+		speed, power, ok := strings.Cut(strings.TrimSpace(field), ":")
+		if !ok {
+			return fmt.Errorf("want speed:power, got %q", field)
+		}
+		var (
+			state pstate
+			err   error
+		)
+		if state.speed, err = strconv.ParseInt(speed, 10, 64); err != nil {
+			return err
+		}
+		if state.power, err = strconv.ParseFloat(power, 64); err != nil {
+			return err
+		}
+		states = append(states, state)
+	}
+	m.states = states
+
+	return nil
+}
+
+// validate returns an error if the CPU can't run the processes.
+func (m cpuModel) validate(processes []Process) error {
+	if len(m.states) == 0 || m.states[0].speed != 100 {
+		return fmt.Errorf("%w: the first frequency state must be full speed (100)", ErrInvalidArgs)
+	}
+	for i, s := range m.states {
+		switch {
+		case s.speed < 1 || s.speed > 100:
+			return fmt.Errorf("%w: frequency state %d: speed must be 1 to 100, got %d", ErrInvalidArgs, i, s.speed)
+		case i > 0 && s.speed >= m.states[i-1].speed:
+			return fmt.Errorf("%w: frequency state %d: speeds must decrease", ErrInvalidArgs, i)
+		case s.power < 0 || math.IsNaN(s.power) || math.IsInf(s.power, 0):
+			return fmt.Errorf("%w: frequency state %d: invalid power %v", ErrInvalidArgs, i, s.power)
+		}
+	}
+	if m.idlePower < 0 || math.IsNaN(m.idlePower) || math.IsInf(m.idlePower, 0) {
+		return fmt.Errorf("%w: invalid idle power %v", ErrInvalidArgs, m.idlePower)
+	}
+
+	// Every process must be able to finish at the slowest speed without the clock overflowing
+	var lastStart, totalWork int64
+	for _, p := range processes {
+		totalWork += m.stretch(p.BurstDuration, len(m.states)-1)
+		if totalWork < 0 {
+			break
+		}
+		lastStart = maxi(lastStart, p.ArrivalTime)
+	}
+	if totalWork < 0 || totalWork > math.MaxInt64-lastStart {
+		return fmt.Errorf("%w: schedule would run past the largest representable time at the slowest speed", ErrInvalidArgs)
+	}
+
+	return nil
+}
+
+// speed returns the speed of a frequency level, where level 0 is full speed even without any states.
+func (m cpuModel) speed(level int) int64 {
+	if level == 0 || level >= len(m.states) {
+		return 100
+	}
+	return m.states[level].speed
+}
+
+// stretch returns how long work measured at full speed takes at a frequency level, or a negative duration
+// if it overflows.
+func (m cpuModel) stretch(work int64, level int) int64 {
+	speed := m.speed(level)
+	if speed == 100 {
+		return work
+	}
+	if work > (math.MaxInt64-99)/100 {
+		return -1
+	}
+	return (work*100 + speed - 1) / speed
+}
+
+// fullSpeed is an amount of work measured at full speed, in ticks and hundredths of a tick.
+type fullSpeed struct {
+	ticks, hundredths int64
+}
+
+// fullSpeed returns how long a slice would have run at full speed.
+func (m cpuModel) fullSpeed(slice TimeSlice) fullSpeed {
+	d, speed := slice.Stop-slice.Start, m.speed(slice.Level)
+	return fullSpeed{ticks: d/100*speed + d%100*speed/100, hundredths: d % 100 * speed % 100}
+}
+
+func (w fullSpeed) add(v fullSpeed) fullSpeed {
+	hundredths := w.hundredths + v.hundredths
+	return fullSpeed{ticks: w.ticks + v.ticks + hundredths/100, hundredths: hundredths % 100}
+}
+
+// energy returns the energy the CPUs of a schedule use until its last process exits, split into the energy
+// used running processes and while idle, in units of power over units of time.
+func (r Result) energy() (busy, idle float64) {
+	m := energyMeter{cpu: r.CPU, cpus: 1}
+	for _, slice := range r.Gantt {
+		m.add(slice)
+	}
+	return m.energy(r.makespan(), r.Time)
+}
+
+// energyMeter adds up the energy the CPUs use running the slices of a schedule as they run.
+type energyMeter struct {
+	cpu     cpuModel
+	cpus    int
+	running int64
+	busy    float64
+}
+
+func (m *energyMeter) add(slice TimeSlice) {
+	if slice.Idle {
+		return
+	}
+	m.cpus = int(maxi(int64(m.cpus), int64(slice.CPU)+1))
+	m.running += slice.Stop - slice.Start
+	if slice.Level < len(m.cpu.states) {
+		m.busy += float64(slice.Stop-slice.Start) * m.cpu.states[slice.Level].power
+	}
+}
+
+// energy returns the energy used running the slices, and while idle until makespan, on a time scale.
+func (m energyMeter) energy(makespan int64, scale timeScale) (busy, idle float64) {
+	idle = float64(int64(m.cpus)*makespan-m.running) * m.cpu.idlePower
+
+	// Power is drawn over units of time rather than ticks
+	perUnit := float64(scale.perUnit())
+	return m.busy / perUnit, idle / perUnit
+}
+
+// dvfs schedules processes first-come, first-serve without preemption, scaling the CPU's frequency with the
+// load like an on-demand governor: each process runs at the slowest speed when no other process is ready,
+// and one frequency state faster for each other process that is.
+func dvfs(processes []Process, memory memoryConfig, cpu cpuModel, ties tieBreak) Result {
+	return dvfsDiscipline(cpu, ties).schedule(processes, memory, cpu)
+}
+
+func dvfsDiscipline(cpu cpuModel, ties tieBreak) discipline {
+	return discipline{ties: ties, run: func(_ *Process, remaining int64, waiting int) (int64, int) {
+		// Slow down further the fewer processes are waiting
+		return remaining, int(maxi(0, int64(len(cpu.states)-1-waiting)))
+	}}
+}
+
+// energySection reports the energy a schedule uses, split into running and idle energy.
+func (r Result) energySection() reportSection {
+	busy, idle := r.energy()
+	return reportSection{lines: []string{fmt.Sprintf("Energy: %.2f (running %.2f, idle %.2f)", busy+idle, busy, idle)}}
+}
@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_cpuModel_Set(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		flag    string
+		want    []pstate
+		wantErr bool
+	}{
+		{
+			name: "states",
+			flag: "100:8, 60:2.5",
+			want: []pstate{{speed: 100, power: 8}, {speed: 60, power: 2.5}},
+		},
+		{
+			name:    "missing power",
+			flag:    "100",
+			wantErr: true,
+		},
+		{
+			name:    "bad speed",
+			flag:    "fast:8",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var m cpuModel
+			err := m.Set(tt.flag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(m.states, tt.want) {
+				t.Errorf("Set() states = %v, want %v", m.states, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cpuModel_validate(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5}}
+	tests := []struct {
+		name      string
+		cpu       cpuModel
+		processes []Process
+		wantErr   error
+	}{
+		{
+			name:      "default",
+			cpu:       defaultParams.cpu,
+			processes: processes,
+		},
+		{
+			name:      "not full speed first",
+			cpu:       cpuModel{states: []pstate{{speed: 80, power: 1}}},
+			processes: processes,
+			wantErr:   ErrInvalidArgs,
+		},
+		{
+			name:      "speeds not decreasing",
+			cpu:       cpuModel{states: []pstate{{speed: 100, power: 8}, {speed: 100, power: 4}}},
+			processes: processes,
+			wantErr:   ErrInvalidArgs,
+		},
+		{
+			name:      "negative power",
+			cpu:       cpuModel{states: []pstate{{speed: 100, power: -1}}},
+			processes: processes,
+			wantErr:   ErrInvalidArgs,
+		},
+		{
+			name:      "negative idle power",
+			cpu:       cpuModel{states: []pstate{{speed: 100, power: 8}}, idlePower: -1},
+			processes: processes,
+			wantErr:   ErrInvalidArgs,
+		},
+		{
+			name:      "overflows at the slowest speed",
+			cpu:       cpuModel{states: []pstate{{speed: 100, power: 8}, {speed: 1, power: 1}}},
+			processes: []Process{{ProcessID: 1, BurstDuration: math.MaxInt64 / 10}},
+			wantErr:   ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.cpu.validate(tt.processes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResult_energy(t *testing.T) {
+	t.Parallel()
+	res := Result{
+		Gantt: []TimeSlice{
+			{PID: 1, Start: 0, Stop: 4},
+			{PID: 2, Start: 2, Stop: 6, CPU: 1, Level: 1},
+		},
+		Stats: []ProcessStats{
+			{Process: Process{ProcessID: 1, BurstDuration: 4}, Exit: 4},
+			{Process: Process{ProcessID: 2, BurstDuration: 2}, Exit: 6},
+		},
+		CPU: cpuModel{states: []pstate{{speed: 100, power: 8}, {speed: 50, power: 2}}, idlePower: 0.5},
+	}
+	// Both CPUs are idle for 2 of the 6 ticks to the last exit.
+	busy, idle := res.energy()
+	if busy != 40 || idle != 2 {
+		t.Errorf("energy() = %v, %v, want 40, 2", busy, idle)
+	}
+}
+
+func Test_dvfs(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 4, ArrivalTime: 20, BurstDuration: 2},
+	}
+	cpu := cpuModel{states: []pstate{{speed: 100, power: 8}, {speed: 75, power: 4.5}, {speed: 50, power: 2}}}
+	// Process 1 runs alone at half speed, process 2 at 75% speed with process 3 waiting, and the rest alone again.
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 6, Level: 2},
+		{PID: 2, Start: 6, Stop: 10, Level: 1},
+		{PID: 3, Start: 10, Stop: 14, Level: 2},
+		{PID: 4, Start: 20, Stop: 24, Level: 2},
+	}
+	res := dvfs(processes, memoryConfig{}, cpu, nil)
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("dvfs() Gantt = %v, want %v", res.Gantt, want)
+	}
+	if err := verifySchedule(processes, res, true); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// historyColumn sets the prior bursts of a process from a column of times separated by semicolons, e.g. "4;6".
+func historyColumn(p *Process, s string, scale timeScale) error {
+	p.History = nil
+	for _, field := range strings.Split(s, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		burst, err := scale.parse(field)
+		if err != nil {
+			return err
+		}
+		p.History = append(p.History, burst)
+	}
+
+	return nil
+}
+
+type (
+	// burstPrediction is the burst predictive SJF expected a process to run for when it ran it, and the burst
+	// it ran.
+	burstPrediction struct {
+		PID       int64
+		Predicted float64
+		Actual    int64
+	}
+	// predictionReport is how well predictive SJF predicted bursts, and how the schedule compares to SJF with
+	// every burst known in advance.
+	predictionReport struct {
+		alpha  float64
+		bursts []burstPrediction
+		// oracleWait and oracleTurnaround are the averages of the oracle SJF schedule.
+		oracleWait       float64
+		oracleTurnaround float64
+	}
+)
+
+// burstPredictor orders ready processes by their predicted next CPU burst, shortest first, by exponential
+// averaging: the prediction after a burst t is alpha·t + (1 − alpha) times the prediction before it.
+// Each process starts from the estimate, averaged over its history of prior bursts.
+// An oracle predicts every burst exactly.
+type burstPredictor struct {
+	alpha    float64
+	estimate int64
+	// io blocks processes, which ends their burst.
+	io     ioModel
+	ties   tieBreak
+	oracle bool
+	// next is the predicted next burst of each process, and left its remaining work.
+	next   map[*Process]float64
+	left   map[*Process]int64
+	bursts []burstPrediction
+}
+
+func newBurstPredictor(p Params, oracle bool) *burstPredictor {
+	return &burstPredictor{
+		alpha:    p.alpha,
+		estimate: p.estimate,
+		io:       p.io,
+		ties:     p.tieBreak,
+		oracle:   oracle,
+		next:     make(map[*Process]float64),
+		left:     make(map[*Process]int64),
+	}
+}
+
+// predict returns the predicted next burst of a process.
+func (b *burstPredictor) predict(p *Process) float64 {
+	if _, ok := b.next[p]; !ok {
+		tau := float64(b.estimate)
+		for _, t := range p.History {
+			tau = b.alpha*float64(t) + (1-b.alpha)*tau
+		}
+		b.next[p] = tau
+		b.left[p] = p.BurstDuration
+	}
+	if b.oracle {
+		return float64(b.burst(b.left[p]))
+	}
+	return b.next[p]
+}
+
+// burst returns the next burst of a process with remaining work, which runs until it blocks on I/O or exits.
+func (b *burstPredictor) burst(remaining int64) int64 {
+	if b.io.every > 0 {
+		return mini(remaining, b.io.every)
+	}
+	return remaining
+}
+
+// discipline runs processes without preemption, shortest predicted burst first, until they block or exit.
+func (b *burstPredictor) discipline() discipline {
+	return discipline{
+		less: func(p, q *Process) bool {
+			return b.predict(p) < b.predict(q)
+		},
+		run: func(p *Process, remaining int64, _ int) (int64, int) {
+			predicted, actual := b.predict(p), b.burst(remaining)
+			b.bursts = append(b.bursts, burstPrediction{PID: p.ProcessID, Predicted: predicted, Actual: actual})
+			b.next[p] = b.alpha*float64(actual) + (1-b.alpha)*b.next[p]
+			b.left[p] = remaining - actual
+			return remaining, 0
+		},
+		io:   b.io,
+		ties: b.ties,
+	}
+}
+
+// predictiveSJF schedules processes shortest predicted burst first, reporting each prediction against the
+// burst the process ran and the averages of the same schedule by oracle SJF.
+func predictiveSJF(processes []Process, p Params) Result {
+	run := func(oracle bool) (Result, *burstPredictor) {
+		b := newBurstPredictor(p, oracle)
+		return b.discipline().schedule(processes, p.memory, cpuModel{}), b
+	}
+	res, predicted := run(false)
+	oracle, _ := run(true)
+	wait, turnaround, _ := oracle.averages()
+	res.Prediction = &predictionReport{
+		alpha:            p.alpha,
+		bursts:           predicted.bursts,
+		oracleWait:       wait,
+		oracleTurnaround: turnaround,
+	}
+	return res
+}
+
+// predictionSection tables each predicted burst against the actual burst, in the order they ran, with the mean
+// absolute error, and reports how much longer processes waited than under oracle SJF.
+func (r Result) predictionSection() reportSection {
+	var (
+		ts       = r.Time
+		report   = r.Prediction
+		absError float64
+		table    = &scheduleTable{header: []string{"ID", ts.label("Predicted"), ts.label("Actual"), ts.label("Error")}}
+	)
+	for _, b := range report.bursts {
+		diff := b.Predicted - float64(b.Actual)
+		absError += math.Abs(diff)
+		table.rows = append(table.rows, []string{fmt.Sprint(b.PID), ts.formatAverage(b.Predicted), ts.format(b.Actual), ts.formatAverage(diff)})
+	}
+	if len(report.bursts) > 0 {
+		absError /= float64(len(report.bursts))
+	}
+	table.footer = []string{"", "", "Mean absolute", ts.formatAverage(absError)}
+
+	wait, turnaround, _ := r.averages()
+	return reportSection{
+		title: fmt.Sprintf("Burst prediction (alpha %g)", report.alpha),
+		table: table,
+		lines: []string{fmt.Sprintf("Penalty against oracle SJF: average wait %s, average turnaround %s",
+			signed(ts.formatAverage(wait-report.oracleWait)), signed(ts.formatAverage(turnaround-report.oracleTurnaround)))},
+	}
+}
+
+// signed prefixes a formatted number with + unless it is negative.
+func signed(s string) string {
+	if strings.HasPrefix(s, "-") {
+		return s
+	}
+	return "+" + s
+}
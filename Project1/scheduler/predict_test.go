@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_historyColumn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		csv     string
+		want    []int64
+		wantErr error
+	}{
+		{name: "bursts", csv: "id,burst,arrival,history\n1,5,0,4; 6;2\n", want: []int64{4, 6, 2}},
+		{name: "empty", csv: "id,burst,arrival,history\n1,5,0,\n"},
+		{name: "not a time", csv: "id,burst,arrival,history\n1,5,0,4;x\n", wantErr: ErrInvalidProcesses},
+		{name: "zero burst", csv: "id,burst,arrival,history\n1,5,0,0\n", wantErr: ErrInvalidProcesses},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes, err := loadProcesses(strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(processes[0].History, tt.want) {
+				t.Errorf("History = %v, want %v", processes[0].History, tt.want)
+			}
+		})
+	}
+}
+
+func Test_predictiveSJF(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		alpha     float64
+		estimate  int64
+		io        ioModel
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantGantt  []TimeSlice
+		wantBursts []burstPrediction
+		wantOracle [2]float64
+	}{
+		{
+			name: "history outweighs the estimate",
+			args: args{processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, History: []int64{9}},
+				{ProcessID: 2, BurstDuration: 6, History: []int64{1}},
+			}, alpha: 0.5, estimate: 5},
+			wantGantt: []TimeSlice{{PID: 2, Start: 0, Stop: 6}, {PID: 1, Start: 6, Stop: 8}},
+			wantBursts: []burstPrediction{
+				{PID: 2, Predicted: 3, Actual: 6},
+				{PID: 1, Predicted: 7, Actual: 2},
+			},
+			wantOracle: [2]float64{1, 5},
+		},
+		{
+			name: "bursts between I/O update the prediction",
+			args: args{processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 3, History: []int64{6}},
+			}, alpha: 1, estimate: 3, io: ioModel{every: 2, time: 1}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6},
+				{PID: 2, Start: 6, Stop: 7},
+			},
+			wantBursts: []burstPrediction{
+				{PID: 1, Predicted: 3, Actual: 2},
+				{PID: 2, Predicted: 6, Actual: 2},
+				{PID: 1, Predicted: 2, Actual: 2},
+				{PID: 2, Predicted: 2, Actual: 1},
+			},
+			wantOracle: [2]float64{2, 6.5},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := predictiveSJF(tt.args.processes, Params{alpha: tt.args.alpha, estimate: tt.args.estimate, io: tt.args.io})
+			if !reflect.DeepEqual(res.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", res.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(res.Prediction.bursts, tt.wantBursts) {
+				t.Errorf("bursts = %v, want %v", res.Prediction.bursts, tt.wantBursts)
+			}
+			if got := [2]float64{res.Prediction.oracleWait, res.Prediction.oracleTurnaround}; got != tt.wantOracle {
+				t.Errorf("oracle averages = %v, want %v", got, tt.wantOracle)
+			}
+			if err := verifySchedule(tt.args.processes, res, true); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
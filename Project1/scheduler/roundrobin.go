@@ -0,0 +1,74 @@
+package scheduler
+
+import "sort"
+
+// onEngine returns how a policy runs the discipline for its parameters on one CPU, admitting processes into memory.
+func onEngine(policy func(p Params) discipline) func(processes []Process, p Params) Result {
+	return func(processes []Process, p Params) Result {
+		return policy(p).schedule(processes, p.memory, cpuModel{})
+	}
+}
+
+// roundRobinVariant applies the parameters every round-robin policy shares to a discipline:
+// • with -arrivals-first, processes arriving as another is preempted queue ahead of it rather than behind
+// • with -io-every, processes block on I/O for -io-time after each interval of work
+// • with -tie-break, processes admitted together queue in the order of the chain
+func roundRobinVariant(d discipline, p Params) discipline {
+	d.arrivalsFirst = p.arrivalsFirst
+	d.io = p.io
+	d.ties = p.tieBreak
+	return d
+}
+
+// roundRobinPolicy preempts each process after at most the time quantum.
+func roundRobinPolicy(p Params) discipline {
+	return roundRobinVariant(roundRobinDiscipline(p.timeQuantum), p)
+}
+
+// weightedRoundRobin gives higher priority processes longer quanta: priority 1 runs for p.weights quanta,
+// and each lower priority for one quantum fewer, down to a single quantum.
+func weightedRoundRobin(p Params) discipline {
+	return roundRobinVariant(discipline{run: func(proc *Process, remaining int64, _ int) (int64, int) {
+		priority := proc.Priority
+		if priority < 1 {
+			priority = 1
+		}
+		weight := p.weights + 1 - mini(priority, p.weights)
+		return mini(remaining, weight*p.timeQuantum), 0
+	}}, p)
+}
+
+// virtualRoundRobin is round-robin where a process returning from I/O waits in an auxiliary queue, ahead of
+// the ready queue, and then runs for the rest of the quantum it blocked during, so processes that block on
+// I/O get as much of the CPU as those that don't.
+func virtualRoundRobin(p Params) discipline {
+	d := roundRobinPolicy(p)
+	d.virtual = true
+	return d
+}
+
+// adaptiveRoundRobin sets the quantum to the median remaining work of the ready processes at the start of
+// each round, once every process ready at the start of the last round has run, so short processes tend to
+// finish in one quantum and long ones aren't preempted too often.
+func adaptiveRoundRobin(p Params) discipline {
+	quantum := p.timeQuantum
+	return roundRobinVariant(discipline{
+		round: func(remaining []int64) {
+			quantum = median(remaining)
+		},
+		run: func(_ *Process, remaining int64, _ int) (int64, int) {
+			return mini(remaining, quantum), 0
+		},
+	}, p)
+}
+
+// median returns the median of positive values, reordering them, rounding the mean of the middle two up.
+func median(values []int64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	lo, hi := values[mid-1], values[mid]
+	return lo + (hi-lo+1)/2
+}
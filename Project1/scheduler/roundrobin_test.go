@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundRobinVariants(t *testing.T) {
+	t.Parallel()
+	// Process 2 arrives as process 1 is preempted
+	arriving := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2, Priority: 1},
+	}
+	type args struct {
+		policy    func(p Params) discipline
+		processes []Process
+		params    Params
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantGantt []TimeSlice
+		wantIO    []TimeSlice
+	}{
+		{
+			name: "preempted process ahead of arrivals",
+			args: args{policy: roundRobinPolicy, processes: arriving, params: Params{timeQuantum: 2}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6},
+			},
+		},
+		{
+			name: "arrivals ahead of preempted process",
+			args: args{policy: roundRobinPolicy, processes: arriving, params: Params{timeQuantum: 2, arrivalsFirst: true}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6},
+			},
+		},
+		{
+			name: "weighted by priority",
+			args: args{policy: weightedRoundRobin, processes: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 1},
+				{ProcessID: 2, BurstDuration: 5, Priority: 2},
+				{ProcessID: 3, BurstDuration: 2, Priority: 9},
+			}, params: Params{timeQuantum: 1, weights: 3}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 3, Start: 5, Stop: 6},
+				{PID: 1, Start: 6, Stop: 8}, {PID: 2, Start: 8, Stop: 10}, {PID: 3, Start: 10, Stop: 11},
+				{PID: 2, Start: 11, Stop: 12},
+			},
+		},
+		{
+			name: "blocked on I/O",
+			args: args{policy: roundRobinPolicy, processes: []Process{
+				{ProcessID: 1, BurstDuration: 3},
+				{ProcessID: 2, BurstDuration: 4},
+			}, params: Params{timeQuantum: 3, io: ioModel{every: 2, time: 3}}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 5, Stop: 6},
+				{PID: 2, Start: 7, Stop: 9},
+			},
+			wantIO: []TimeSlice{{PID: 1, Start: 2, Stop: 5}, {PID: 2, Start: 4, Stop: 7}},
+		},
+		{
+			name: "virtual returns from I/O ahead of the ready queue for the rest of its quantum",
+			args: args{policy: virtualRoundRobin, processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 8},
+				{ProcessID: 3, BurstDuration: 8},
+			}, params: Params{timeQuantum: 4, io: ioModel{every: 3, time: 1}}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 6}, {PID: 1, Start: 6, Stop: 7},
+				{PID: 2, Start: 7, Stop: 8}, {PID: 3, Start: 8, Stop: 11}, {PID: 2, Start: 11, Stop: 13},
+				{PID: 3, Start: 13, Stop: 14}, {PID: 2, Start: 14, Stop: 16}, {PID: 3, Start: 16, Stop: 18},
+				{PID: 3, Start: 19, Stop: 21},
+			},
+			wantIO: []TimeSlice{
+				{PID: 1, Start: 3, Stop: 4}, {PID: 2, Start: 6, Stop: 7}, {PID: 3, Start: 11, Stop: 12},
+				{PID: 2, Start: 13, Stop: 14}, {PID: 3, Start: 18, Stop: 19},
+			},
+		},
+		{
+			name: "adaptive quantum is the median remaining burst",
+			args: args{policy: adaptiveRoundRobin, processes: []Process{
+				{ProcessID: 1, BurstDuration: 9},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, BurstDuration: 4},
+			}, params: Params{timeQuantum: 1}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 10},
+				{PID: 1, Start: 10, Stop: 15},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := tt.args.policy(tt.args.params).schedule(tt.args.processes, memoryConfig{}, cpuModel{})
+			if !reflect.DeepEqual(res.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", res.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(res.IO, tt.wantIO) {
+				t.Errorf("IO = %v, want %v", res.IO, tt.wantIO)
+			}
+			if err := verifySchedule(tt.args.processes, res, true); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_median(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		values []int64
+		want   int64
+	}{
+		{name: "one", values: []int64{7}, want: 7},
+		{name: "odd", values: []int64{9, 1, 4}, want: 4},
+		{name: "even rounds up", values: []int64{8, 1, 2, 5}, want: 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := median(tt.values); got != tt.want {
+				t.Errorf("median() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
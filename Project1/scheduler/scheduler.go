@@ -0,0 +1,881 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type (
+	Process struct {
+		ProcessID     int64
+		ArrivalTime   int64
+		BurstDuration int64
+		Priority      int64
+		// JobID groups the threads of a job; zero means the process is a job of its own.
+		JobID int64
+		// Owner is the user or group the process belongs to, which fair-share scheduling divides the CPU among.
+		Owner string
+		// Memory is how much memory the process needs before it can be admitted to the ready queue.
+		Memory int64
+		// DependsOn lists the IDs of the processes that must complete before this one can start.
+		DependsOn []int64
+		// History lists the durations of the process's prior CPU bursts, oldest first, which predictive SJF
+		// predicts its bursts from.
+		History []int64
+	}
+	TimeSlice struct {
+		PID   int64
+		Start int64
+		Stop  int64
+		CPU   int
+		// Level is the frequency state of the CPU during the slice; zero is full speed.
+		Level int
+		// Idle slices chart the time a CPU waits for a process to arrive, and run no process.
+		Idle bool
+	}
+	// ProcessStats is the timing of a single process in a finished schedule.
+	ProcessStats struct {
+		Process
+		// Admission is how long the process waited for memory and for the processes it depends on before it
+		// was admitted to the ready queue, which is part of its Wait.
+		Admission int64
+		Wait      int64
+		// IO is how long the process was blocked on I/O, which isn't part of its Wait.
+		IO         int64
+		Turnaround int64
+		Exit       int64
+	}
+	// Result is a finished schedule: the GANTT chart and the timing of every process,
+	// in the same order as the processes were given.
+	Result struct {
+		Gantt []TimeSlice
+		Stats []ProcessStats
+		// IO holds the times processes were blocked on I/O, which are on no CPU.
+		IO []TimeSlice
+		// MemoryCapacity is the memory processes were admitted into; zero means memory was unlimited.
+		MemoryCapacity int64
+		// CPU is the power model of the CPUs the processes ran on.
+		CPU cpuModel
+		// Time is the scale times are output in.
+		Time timeScale
+		// Chart is how the GANTT chart is drawn in the terminal.
+		Chart ganttStyle
+		// Timeline adds each process's state transitions to the report.
+		Timeline bool
+		// TieBreak is the chain of keys that ordered processes the policy found equal, named in the report.
+		TieBreak tieBreak
+		// Prediction is how well predictive SJF predicted bursts; nil for every other policy.
+		Prediction *predictionReport
+	}
+)
+
+// averages returns the average waiting time, average turnaround time and throughput of a schedule.
+func (r Result) averages() (wait, turnaround, throughput float64) {
+	var lastCompletion int64
+	for i := range r.Stats {
+		wait += float64(r.Stats[i].Wait)
+		turnaround += float64(r.Stats[i].Turnaround)
+		if r.Stats[i].Exit > lastCompletion {
+			lastCompletion = r.Stats[i].Exit
+		}
+	}
+	count := float64(len(r.Stats))
+
+	return wait / count, turnaround / count, count / float64(lastCompletion)
+}
+
+// scheduleTable is the table of timing of a schedule.
+type scheduleTable struct {
+	header []string
+	rows   [][]string
+	footer []string
+}
+
+// table returns the table of timing of a schedule,
+// splitting each wait into admission delay and ready-queue wait when processes were admitted into memory,
+// with the time each process was blocked on I/O, before its turnaround, when any was,
+// and with the owner of each process and its owner's share of the CPU, after its ID, when any has an owner.
+func (r Result) table() scheduleTable {
+	t := r.timingTable()
+	if len(r.IO) > 0 {
+		at := len(t.header) - 2
+		t.header = insertColumn(t.header, at, r.Time.label("I/O"))
+		t.footer = insertColumn(t.footer, at, "")
+		for i, s := range r.Stats {
+			t.rows[i] = insertColumn(t.rows[i], at, r.Time.format(s.IO))
+		}
+	}
+	if share := r.ownerShare(); share != nil {
+		t.header = insertColumn(insertColumn(t.header, 1, "Owner"), 2, "Owner CPU share")
+		t.footer = insertColumn(insertColumn(t.footer, 1, ""), 2, "")
+		for i, s := range r.Stats {
+			t.rows[i] = insertColumn(insertColumn(t.rows[i], 1, s.Owner), 2, fmt.Sprintf("%.1f%%", share[s.Owner]))
+		}
+	}
+	return t
+}
+
+// insertColumn inserts the value of a column into a row of a table.
+func insertColumn(row []string, at int, value string) []string {
+	row = append(row, "")
+	copy(row[at+1:], row[at:])
+	row[at] = value
+	return row
+}
+
+// timingTable returns the table of timing of a schedule, without I/O.
+func (r Result) timingTable() scheduleTable {
+	var (
+		wait, turnaround, throughput = r.averages()
+		ts                           = r.Time
+		rate                         = "Throughput\n" + ts.formatRate(throughput)
+		idle                         string
+	)
+	// The footer reports the time the CPUs are idle under the burst durations when there is any
+	if t := r.idle(); t > 0 {
+		idle = "CPU idle\n" + ts.format(t)
+	}
+	if r.MemoryCapacity == 0 {
+		t := scheduleTable{
+			header: []string{"ID", "Priority", ts.label("Burst"), ts.label("Arrival"), ts.label("Wait"), ts.label("Turnaround"), ts.label("Exit")},
+			rows:   make([][]string, len(r.Stats)),
+			footer: []string{"", "", idle, "",
+				"Average\n" + ts.formatAverage(wait),
+				"Average\n" + ts.formatAverage(turnaround),
+				rate},
+		}
+		for i, s := range r.Stats {
+			t.rows[i] = []string{
+				fmt.Sprint(s.ProcessID),
+				fmt.Sprint(s.Priority),
+				ts.format(s.BurstDuration),
+				ts.format(s.ArrivalTime),
+				ts.format(s.Wait),
+				ts.format(s.Turnaround),
+				ts.format(s.Exit),
+			}
+		}
+		return t
+	}
+
+	var admission float64
+	for _, s := range r.Stats {
+		admission += float64(s.Admission)
+	}
+	admission /= float64(len(r.Stats))
+	t := scheduleTable{
+		header: []string{"ID", "Priority", ts.label("Burst"), "Memory", ts.label("Arrival"), ts.label("Admission"),
+			ts.label("Ready wait"), ts.label("Turnaround"), ts.label("Exit")},
+		rows: make([][]string, len(r.Stats)),
+		footer: []string{"", "", idle, "", "",
+			"Average\n" + ts.formatAverage(admission),
+			"Average\n" + ts.formatAverage(wait-admission),
+			"Average\n" + ts.formatAverage(turnaround),
+			rate},
+	}
+	for i, s := range r.Stats {
+		t.rows[i] = []string{
+			fmt.Sprint(s.ProcessID),
+			fmt.Sprint(s.Priority),
+			ts.format(s.BurstDuration),
+			fmt.Sprint(s.Memory),
+			ts.format(s.ArrivalTime),
+			ts.format(s.Admission),
+			ts.format(s.Wait - s.Admission),
+			ts.format(s.Turnaround),
+			ts.format(s.Exit),
+		}
+	}
+
+	return t
+}
+
+//region Schedulers
+
+// Params are the tunable parameters of the scheduling policies and the time scale of the processes, set by the
+// names of their command-line flags.
+type Params struct {
+	// timeQuantum is in ticks; quantum is the -quantum flag, in units of the time scale.
+	timeQuantum int64
+	quantum     string
+	// arrivalsFirst, weights and io are how the round-robin policies run; ioEvery and ioTime are the
+	// -io-every and -io-time flags, in units of the time scale.
+	arrivalsFirst bool
+	weights       int64
+	io            ioModel
+	ioEvery       string
+	ioTime        string
+	// alpha weighs the last burst against the prediction before it in predictive SJF, which predicts
+	// estimate for a process with no history; estimateFlag is the -estimate flag, in units of the time scale.
+	alpha        float64
+	estimate     int64
+	estimateFlag string
+	cpus         int
+	// plugin is the command line of the plugin policy's program.
+	plugin string
+	// tieBreak orders processes every policy finds equal.
+	tieBreak tieBreak
+	// shares weighs the owners fair-share scheduling divides the CPU among.
+	shares   ownerShares
+	memory   memoryConfig
+	cpu      cpuModel
+	time     timeScale
+	chart    ganttStyle
+	timeline bool
+}
+
+// validate returns an error if the parameters can't be used to schedule.
+func (p Params) validate() error {
+	if p.timeQuantum < 1 {
+		return fmt.Errorf("%w: time quantum must be positive, got %d", ErrInvalidArgs, p.timeQuantum)
+	}
+	if p.cpus < 1 {
+		return fmt.Errorf("%w: need at least one CPU, got %d", ErrInvalidArgs, p.cpus)
+	}
+	if p.weights < 1 {
+		return fmt.Errorf("%w: weights must be positive, got %d", ErrInvalidArgs, p.weights)
+	}
+	if p.io.every < 0 || p.io.every > 0 && p.io.time < 1 {
+		return fmt.Errorf("%w: processes must run and block on I/O for positive times, got %d and %d",
+			ErrInvalidArgs, p.io.every, p.io.time)
+	}
+	if p.chart.width < 0 || p.chart.width > maxGanttWidth {
+		return fmt.Errorf("%w: Gantt chart width must be 0 to %d columns, got %d", ErrInvalidArgs, maxGanttWidth,
+			p.chart.width)
+	}
+	if p.alpha < 0 || p.alpha > 1 || p.estimate < 0 {
+		return fmt.Errorf("%w: alpha must be between 0 and 1 and the estimate not negative, got %g and %d",
+			ErrInvalidArgs, p.alpha, p.estimate)
+	}
+
+	return p.time.validate()
+}
+
+// Parse converts the times given by flags to ticks of the time scale, and validates the parameters.
+func (p *Params) Parse() error {
+	if err := p.time.validate(); err != nil {
+		return err
+	}
+	for _, t := range []struct {
+		name  string
+		flag  string
+		ticks *int64
+	}{
+		{name: "quantum", flag: p.quantum, ticks: &p.timeQuantum},
+		{name: "io-every", flag: p.ioEvery, ticks: &p.io.every},
+		{name: "io-time", flag: p.ioTime, ticks: &p.io.time},
+		{name: "estimate", flag: p.estimateFlag, ticks: &p.estimate},
+	} {
+		if t.flag == "" {
+			continue
+		}
+		ticks, err := p.time.parse(t.flag)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArgs, t.name, err)
+		}
+		*t.ticks = ticks
+	}
+
+	return p.validate()
+}
+
+// Flags defines a flag for each parameter on fs, defaulting to its current value.
+// Times are in units of the time scale, so the parameters must be parsed once fs is.
+func (p *Params) Flags(fs *flag.FlagSet) {
+	quantum := p.quantum
+	if quantum == "" {
+		quantum = p.time.format(p.timeQuantum)
+	}
+	ioEvery, ioTime := p.ioEvery, p.ioTime
+	if ioEvery == "" {
+		ioEvery = p.time.format(p.io.every)
+	}
+	if ioTime == "" {
+		ioTime = p.time.format(p.io.time)
+	}
+	estimate := p.estimateFlag
+	if estimate == "" {
+		estimate = p.time.format(p.estimate)
+	}
+	fs.StringVar(&p.quantum, "quantum", quantum, "round-robin time quantum, e.g. 2 or 1.5ms")
+	fs.BoolVar(&p.arrivalsFirst, "arrivals-first", p.arrivalsFirst, "round-robin policies queue processes arriving as another is preempted ahead of it")
+	fs.Int64Var(&p.weights, "weights", p.weights, "quanta weighted round-robin gives priority 1, one fewer for each lower priority down to one")
+	fs.StringVar(&p.ioEvery, "io-every", ioEvery, "time round-robin and predictive SJF processes run between blocking on I/O (0 never blocks)")
+	fs.StringVar(&p.ioTime, "io-time", ioTime, "time each I/O blocks a round-robin or predictive SJF process for")
+	fs.Float64Var(&p.alpha, "alpha", p.alpha, "weight predictive SJF gives the last burst when predicting the next, from 0 to 1")
+	fs.StringVar(&p.estimateFlag, "estimate", estimate, "burst predictive SJF predicts for a process with no history")
+	fs.Var(&p.tieBreak, "tie-break", "comma-separated keys that order processes a policy finds equal, first to last: arrival, pid, priority or remaining (empty keeps file order)")
+	fs.Var(&p.shares, "shares", "comma-separated owner:weight pairs that weigh the CPU fair-share gives each owner (unnamed owners weigh 1)")
+	fs.StringVar(&p.plugin, "plugin", p.plugin, "command line of a program the plugin policy asks which ready process to run, and for how long")
+	fs.IntVar(&p.cpus, "cpus", p.cpus, "number of CPUs for gang scheduling")
+	fs.Int64Var(&p.memory.capacity, "memory", p.memory.capacity, "memory capacity processes are admitted into (0 is unlimited)")
+	fs.StringVar(&p.memory.fit, "fit", p.memory.fit, "how memory is allocated to processes: first or best")
+	fs.Var(&p.cpu, "pstates", "CPU frequency states as speed:power pairs, fastest first, where the first speed is 100")
+	fs.Float64Var(&p.cpu.idlePower, "idle-power", p.cpu.idlePower, "power each CPU draws while idle")
+	fs.StringVar(&p.time.unit, "unit", p.time.unit, "unit of time of the processes and output, e.g. ms (empty is unitless)")
+	fs.IntVar(&p.time.precision, "precision", p.time.precision, "decimal places of a unit that times are kept to")
+	fs.IntVar(&p.chart.width, "width", p.chart.width, "columns the Gantt chart wraps at, which time is scaled to fit")
+	fs.BoolVar(&p.chart.colour, "colour", p.chart.colour, "colour each process in the Gantt chart with ANSI escapes")
+	fs.BoolVar(&p.timeline, "timeline", p.timeline, "report each process's state transitions, time in each state, preemptions and response")
+	fs.DurationVar(&p.time.tick, "tick", p.time.tick, "duration of one scheduler tick when importing perf traces without a -unit")
+}
+
+// Set sets the parameter named by its flag, e.g. Set("quantum", "1.5ms"). Times are in units of the time scale,
+// so the parameters must be parsed once they are all set.
+func (p *Params) Set(name, value string) error {
+	fs := flag.NewFlagSet("params", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	p.Flags(fs)
+	if fs.Lookup(name) == nil {
+		return fmt.Errorf("%w: unknown parameter %q", ErrInvalidArgs, name)
+	}
+	if err := fs.Set(name, value); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArgs, name, err)
+	}
+
+	return nil
+}
+
+// DefaultParams returns the parameters the policies use unless they are set.
+func DefaultParams() Params {
+	p := defaultParams
+	p.cpu.states = append([]pstate(nil), defaultParams.cpu.states...)
+	p.tieBreak = append(tieBreak(nil), defaultParams.tieBreak...)
+	return p
+}
+
+// defaultParams are the policy parameters used unless overridden (you can adjust these values as needed).
+var defaultParams = Params{
+	timeQuantum: 2,
+	weights:     4,
+	alpha:       0.5,
+	estimate:    10,
+	cpus:        2,
+	tieBreak:    tieBreak{"arrival", "pid"},
+	memory:      memoryConfig{fit: "first"},
+	chart:       ganttStyle{width: defaultGanttWidth},
+	time:        timeScale{tick: time.Millisecond},
+	cpu: cpuModel{
+		states:    []pstate{{speed: 100, power: 8}, {speed: 75, power: 4.5}, {speed: 50, power: 2}},
+		idlePower: 0.5,
+	},
+}
+
+// Policy is a named scheduling policy run over the loaded processes.
+type Policy struct {
+	name  string
+	title string
+	// workConserving policies never leave the CPU idle while a process is ready.
+	workConserving bool
+	// firstCome policies run each process to completion, never before a process ready before it.
+	firstCome bool
+	run       func(processes []Process, p Params) Result
+	// try runs a policy that can fail, such as a plugin, in place of run.
+	try func(processes []Process, p Params) (Result, error)
+	// stream is how the policy runs on the discrete-event engine, for streaming; nil if it can't.
+	stream func(p Params) discipline
+}
+
+// schedule runs the policy over processes on CPUs of the power model in p, to be output on the time scale in p.
+func (pol Policy) schedule(processes []Process, p Params) Result {
+	return pol.finish(pol.run(processes, p), p)
+}
+
+// finish sets the power model and how it's output on a schedule by the policy.
+func (pol Policy) finish(res Result, p Params) Result {
+	res.CPU = p.cpu
+	res.Time = p.time
+	res.Chart = p.chart
+	res.Timeline = p.timeline
+	res.TieBreak = p.tieBreak
+	return res
+}
+
+// Name returns the short name of the policy, e.g. "rr".
+func (pol Policy) Name() string {
+	return pol.name
+}
+
+// Title returns the title the policy's schedule is rendered under, e.g. "Round-robin".
+func (pol Policy) Title() string {
+	return pol.title
+}
+
+// Schedule runs the policy over processes with the parameters in p, returning an error if the parameters are
+// invalid, the processes can't be scheduled with them, or the processes depend on unknown processes or in a cycle.
+func (pol Policy) Schedule(processes []Process, p Params) (Result, error) {
+	if err := p.Parse(); err != nil {
+		return Result{}, err
+	}
+	if err := p.memory.validate(processes); err != nil {
+		return Result{}, err
+	}
+	// Processes that weren't loaded may depend on processes that can never run
+	if err := validateDependencies(processes); err != nil {
+		return Result{}, err
+	}
+	if err := p.cpu.validate(processes); err != nil {
+		return Result{}, err
+	}
+
+	if pol.try != nil {
+		res, err := pol.try(processes, p)
+		if err != nil {
+			return Result{}, err
+		}
+		return pol.finish(res, p), nil
+	}
+	return pol.schedule(processes, p), nil
+}
+
+// Verify checks a schedule of processes by the policy against the scheduling invariants, returning an error
+// wrapping ErrInvariant for the first one it breaks.
+func (pol Policy) Verify(processes []Process, res Result) error {
+	if err := verifySchedule(processes, res, pol.workConserving); err != nil {
+		return err
+	}
+	if pol.firstCome {
+		return verifyFirstCome(res)
+	}
+	return nil
+}
+
+// Policies returns every scheduling policy in the order they are output.
+func Policies() []Policy {
+	return append([]Policy(nil), policies...)
+}
+
+// LookupPolicy returns the policy with a name.
+func LookupPolicy(name string) (Policy, error) {
+	for _, pol := range append(policies, pluginPolicy) {
+		if pol.name == name {
+			return pol, nil
+		}
+	}
+	return Policy{}, fmt.Errorf("%w: unknown policy %q", ErrInvalidArgs, name)
+}
+
+// policies lists every scheduling policy in the order they are output.
+var policies = []Policy{
+	{name: "fcfs", title: "First-come, first-serve", workConserving: true, firstCome: true, run: func(processes []Process, p Params) Result {
+		return fcfs(processes, p.memory, p.cpu, p.tieBreak)
+	}},
+	{name: "sjf", title: "Shortest-job-first (preemptive)", workConserving: true, run: func(processes []Process, p Params) Result {
+		return shortestFirst(processes, p.memory, sjfPriorityCriteria, p.tieBreak)
+	}, stream: func(p Params) discipline {
+		return discipline{less: sjfPriorityCriteria, run: fullBurst, ties: p.tieBreak}
+	}},
+	{name: "priority", title: "Priority", workConserving: true, run: func(processes []Process, p Params) Result {
+		return shortestFirst(processes, p.memory, shorterBurst, p.tieBreak)
+	}, stream: func(p Params) discipline {
+		return discipline{less: shorterBurst, run: fullBurst, ties: p.tieBreak}
+	}},
+	{name: "psjf", title: "Predictive SJF", workConserving: true, run: predictiveSJF},
+	{name: "rr", title: "Round-robin", workConserving: true, run: onEngine(roundRobinPolicy), stream: roundRobinPolicy},
+	{name: "wrr", title: "Weighted round-robin", workConserving: true, run: onEngine(weightedRoundRobin), stream: weightedRoundRobin},
+	{name: "vrr", title: "Virtual round-robin", workConserving: true, run: onEngine(virtualRoundRobin), stream: virtualRoundRobin},
+	{name: "arr", title: "Adaptive round-robin", workConserving: true, run: onEngine(adaptiveRoundRobin), stream: adaptiveRoundRobin},
+	{name: "fair", title: "Fair-share", workConserving: true, run: onEngine(fairShare), stream: fairShare},
+	{name: "gang", title: "Gang (FCFS jobs)", run: func(processes []Process, p Params) Result {
+		return gang(processes, p.cpus, p.memory, p.tieBreak)
+	}},
+	{name: "dvfs", title: "Energy-aware (DVFS)", workConserving: true, run: func(processes []Process, p Params) Result {
+		return dvfs(processes, p.memory, p.cpu, p.tieBreak)
+	}, stream: func(p Params) discipline {
+		return dvfsDiscipline(p.cpu, p.tieBreak)
+	}},
+}
+
+// pluginPolicy runs the program named by the -plugin parameter. It can be looked up by name, but isn't one of
+// the policies every schedule is run by.
+var pluginPolicy = Policy{name: "plugin", title: "Plugin", try: runPlugin}
+
+// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+func FCFSSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, fcfs(processes, memoryConfig{}, cpuModel{}, nil))
+}
+
+// fcfs schedules processes first-come, first-serve without preemption, in order of arrival and in the order of
+// the tie-break chain when they arrive together, charting the time the CPU waits for the next arrival as idle.
+func fcfs(processes []Process, memory memoryConfig, cpu cpuModel, ties tieBreak) Result {
+	res := discipline{run: fullBurst, ties: ties}.schedule(processes, memory, cpu)
+	res.Gantt = withIdle(res.Gantt)
+	return res
+}
+
+// withIdle returns a GANTT chart with an idle slice wherever a CPU is idle before one of its slices starts,
+// given the slices of each CPU in the order they start.
+func withIdle(gantt []TimeSlice) []TimeSlice {
+	var (
+		charted = make([]TimeSlice, 0, len(gantt))
+		free    = make(map[int]int64)
+	)
+	for _, slice := range gantt {
+		if from := free[slice.CPU]; slice.Start > from {
+			charted = append(charted, TimeSlice{Start: from, Stop: slice.Start, CPU: slice.CPU, Idle: true})
+		}
+		charted = append(charted, slice)
+		free[slice.CPU] = slice.Stop
+	}
+
+	return charted
+}
+
+// idle returns the time the CPUs of a schedule spend idle before its last process exits.
+func (r Result) idle() int64 {
+	m := energyMeter{cpus: 1}
+	for _, slice := range r.Gantt {
+		m.add(slice)
+	}
+	return int64(m.cpus)*r.makespan() - m.running
+}
+
+// arrivalOrder returns the indexes of processes sorted by arrival time,
+// keeping the given order for processes that arrive at the same time.
+func arrivalOrder(processes []Process) []int {
+	order := make([]int, len(processes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return processes[order[a]].ArrivalTime < processes[order[b]].ArrivalTime
+	})
+
+	return order
+}
+
+// shortestFirst schedules processes without preemption, running the first ready process by less first,
+// and the first of equal processes by the tie-break chain, or the first admitted.
+func shortestFirst(processes []Process, memory memoryConfig, less func(a, b *Process) bool, ties tieBreak) Result {
+	return discipline{less: less, run: fullBurst, ties: ties}.schedule(processes, memory, cpuModel{})
+}
+
+// shorterBurst orders processes by burst duration, shortest first.
+func shorterBurst(a, b *Process) bool {
+	return a.BurstDuration < b.BurstDuration
+}
+
+// SJFSchedule performs Shortest-Job-First (preemptive) scheduling
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, shortestFirst(processes, memoryConfig{}, sjfPriorityCriteria, nil))
+}
+
+// sjfPriorityCriteria orders processes for SJF: a process whose priority is its own burst first, unless it is
+// also the other's burst, then not before a process whose burst is its priority, and otherwise shortest burst
+// first.
+func sjfPriorityCriteria(a, b *Process) bool {
+	if a.Priority == a.BurstDuration && a.Priority == b.BurstDuration {
+		return false
+	}
+	if a.Priority == a.BurstDuration {
+		return true
+	}
+	if a.Priority == b.BurstDuration {
+		return false
+	}
+	return a.BurstDuration < b.BurstDuration
+}
+
+// SJFPrioritySchedule performs Shortest-Job-First Priority (preemptive) scheduling
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	outputResult(w, title, shortestFirst(processes, memoryConfig{}, shorterBurst, nil))
+}
+
+// RRSchedule performs Round-Robin (preemptive) scheduling
+func RRSchedule(w io.Writer, title string, processes []Process, timeQuantum int64) {
+	outputResult(w, title, roundRobin(processes, memoryConfig{}, timeQuantum))
+}
+
+// roundRobin schedules processes in arrival order, preempting each after at most timeQuantum.
+// A preempted process goes to the back of the ready queue, ahead of processes admitted as it is preempted.
+func roundRobin(processes []Process, memory memoryConfig, timeQuantum int64) Result {
+	return roundRobinDiscipline(timeQuantum).schedule(processes, memory, cpuModel{})
+}
+
+func roundRobinDiscipline(timeQuantum int64) discipline {
+	return discipline{run: func(_ *Process, remaining int64, _ int) (int64, int) {
+		return mini(remaining, timeQuantum), 0
+	}}
+}
+
+// min returns the minimum of two integers
+func mini(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//endregion
+
+//region Output helpers
+
+// Render outputs the schedule as a titled GANTT chart and a table of timing, followed by the job table,
+// critical path, burst predictions, process timeline and energy when they apply.
+func (r Result) Render(w io.Writer, title string) {
+	outputResult(w, title, r)
+}
+
+// outputResult outputs a finished schedule as a titled GANTT chart and a table of timing.
+func outputResult(w io.Writer, title string, res Result) {
+	outputTitle(w, title)
+	if len(res.TieBreak) > 0 {
+		_, _ = fmt.Fprintln(w, res.TieBreak.header())
+	}
+	outputGantt(w, res.Gantt, res.Time, res.Chart)
+	outputSchedule(w, res.table())
+	for _, s := range res.sections() {
+		if s.title != "" {
+			_, _ = fmt.Fprintln(w, s.title)
+		}
+		if s.table != nil {
+			table := tablewriter.NewWriter(w)
+			table.SetHeader(s.table.header)
+			table.AppendBulk(s.table.rows)
+			if s.table.footer != nil {
+				table.SetFooter(s.table.footer)
+			}
+			table.Render()
+		}
+		for _, line := range s.lines {
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// reportSection is a section of a report after the table of timing: a title, a table and lines of text, any of
+// which it can leave out.
+type reportSection struct {
+	title string
+	table *scheduleTable
+	lines []string
+}
+
+// sections returns the sections of the report of a schedule after its table of timing, in the order they're
+// output, so every report format has the same ones.
+func (r Result) sections() []reportSection {
+	var sections []reportSection
+	if jobs := r.jobStats(); len(jobs) > 0 {
+		sections = append(sections, jobSection(jobs, r.Time))
+	}
+	if r.hasDependencies() {
+		sections = append(sections, r.criticalPathSection())
+	}
+	if r.Prediction != nil {
+		sections = append(sections, r.predictionSection())
+	}
+	if r.Timeline {
+		sections = append(sections, r.timelineSection())
+	}
+	if len(r.CPU.states) > 0 {
+		sections = append(sections, r.energySection())
+	}
+	return sections
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+func outputSchedule(w io.Writer, t scheduleTable) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(t.header)
+	table.AppendBulk(t.rows)
+	table.SetFooter(t.footer)
+	table.Render()
+}
+
+//endregion
+
+//region Loading processes.
+
+var (
+	ErrInvalidArgs      = errors.New("invalid args")
+	ErrInvalidProcesses = errors.New("invalid processes")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+)
+
+// csvColumns sets the Process field for each column of a scheduling file, reading times on a time scale.
+var csvColumns = map[string]func(p *Process, field string, scale timeScale) error{
+	"id":       intColumn(func(p *Process) *int64 { return &p.ProcessID }),
+	"burst":    timeColumn(func(p *Process) *int64 { return &p.BurstDuration }),
+	"arrival":  timeColumn(func(p *Process) *int64 { return &p.ArrivalTime }),
+	"priority": intColumn(func(p *Process) *int64 { return &p.Priority }),
+	"job":      intColumn(func(p *Process) *int64 { return &p.JobID }),
+	"memory":   intColumn(func(p *Process) *int64 { return &p.Memory }),
+	"depends":  dependsColumn,
+	"history":  historyColumn,
+	"owner": func(p *Process, s string, _ timeScale) error {
+		p.Owner = strings.TrimSpace(s)
+		return nil
+	},
+}
+
+// defaultColumns are the columns of a scheduling file without a header row, of which the last is optional.
+var defaultColumns = []string{"id", "burst", "arrival", "priority"}
+
+// intColumn sets an integer Process field from a column.
+func intColumn(field func(p *Process) *int64) func(p *Process, s string, scale timeScale) error {
+	return func(p *Process, s string, _ timeScale) (err error) {
+		*field(p), err = strconv.ParseInt(s, 10, 64)
+		return err
+	}
+}
+
+// timeColumn sets a Process time field from a column.
+func timeColumn(field func(p *Process) *int64) func(p *Process, s string, scale timeScale) error {
+	return func(p *Process, s string, scale timeScale) (err error) {
+		*field(p), err = scale.parse(s)
+		return err
+	}
+}
+
+// loadProcesses reads processes from CSV records of <ProcessID>,<Burst Duration>,<Arrival Time>[,<Priority>],
+// returning an error for any record that can't be scheduled.
+// The file may instead start with a header row naming its columns from csvColumns in any order,
+// e.g. "id,burst,arrival,job", which must include id, burst and arrival.
+// A depends column lists the IDs of the processes each one waits for, separated by semicolons, e.g. "3;5".
+// An owner column names the user or group each one belongs to.
+func loadProcesses(r io.Reader) ([]Process, error) {
+	return loadScaledProcesses(r, timeScale{})
+}
+
+// loadScaledProcesses reads processes like loadProcesses, with times that are decimal numbers or durations
+// on a time scale, e.g. "1.5" or "1.5ms".
+func loadScaledProcesses(r io.Reader, scale timeScale) ([]Process, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV", err)
+	}
+
+	var columns []string
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		if _, err := strconv.ParseInt(rows[0][0], 10, 64); err != nil {
+			if columns, err = csvHeader(rows[0]); err != nil {
+				return nil, err
+			}
+			rows = rows[1:]
+		}
+	}
+
+	processes := make([]Process, len(rows))
+	for i := range rows {
+		if err := parseRecord(&processes[i], rows[i], columns, scale, i+1); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateProcesses(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+// parseRecord sets a process from a CSV record of the columns named by the header row, or of the default
+// columns if there is none.
+func parseRecord(p *Process, row, columns []string, scale timeScale, record int) error {
+	if columns == nil {
+		if len(row) < len(defaultColumns)-1 || len(row) > len(defaultColumns) {
+			return fmt.Errorf("%w: record %d: want 3 or 4 fields, got %d", ErrInvalidProcesses, record, len(row))
+		}
+		columns = defaultColumns[:len(row)]
+	}
+	for j, column := range columns {
+		if err := csvColumns[column](p, row[j], scale); err != nil {
+			return fmt.Errorf("%w: record %d: %s: %v", ErrInvalidProcesses, record, column, err)
+		}
+	}
+
+	return nil
+}
+
+// csvHeader returns the columns named by a header row.
+func csvHeader(header []string) ([]string, error) {
+	var (
+		columns = make([]string, len(header))
+		seen    = make(map[string]bool, len(header))
+	)
+	for i := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(header[i]))
+		if _, ok := csvColumns[columns[i]]; !ok {
+			return nil, fmt.Errorf("%w: header: unknown column %q", ErrInvalidProcesses, header[i])
+		}
+		if seen[columns[i]] {
+			return nil, fmt.Errorf("%w: header: duplicate column %q", ErrInvalidProcesses, header[i])
+		}
+		seen[columns[i]] = true
+	}
+	for _, required := range defaultColumns[:len(defaultColumns)-1] {
+		if !seen[required] {
+			return nil, fmt.Errorf("%w: header: missing column %q", ErrInvalidProcesses, required)
+		}
+	}
+
+	return columns, nil
+}
+
+// validateProcesses returns an error if any of the processes can't be scheduled.
+func validateProcesses(processes []Process) error {
+	var (
+		seen      = make(map[int64]bool, len(processes))
+		lastStart int64
+		totalWork int64
+	)
+	for i, p := range processes {
+		if seen[p.ProcessID] {
+			return fmt.Errorf("%w: record %d: duplicate process ID %d", ErrInvalidProcesses, i+1, p.ProcessID)
+		}
+		if err := validateProcess(p, i+1); err != nil {
+			return err
+		}
+		if p.BurstDuration > math.MaxInt64-totalWork {
+			return fmt.Errorf("%w: record %d: total burst duration overflows", ErrInvalidProcesses, i+1)
+		}
+		seen[p.ProcessID] = true
+		totalWork += p.BurstDuration
+		lastStart = maxi(lastStart, p.ArrivalTime)
+	}
+	// Every process must be able to finish without the clock overflowing
+	if totalWork > math.MaxInt64-lastStart {
+		return fmt.Errorf("%w: schedule would run past the largest representable time", ErrInvalidProcesses)
+	}
+
+	return validateDependencies(processes)
+}
+
+// validateProcess returns an error if a process on its own can't be scheduled.
+func validateProcess(p Process, record int) error {
+	switch {
+	case p.BurstDuration <= 0:
+		return fmt.Errorf("%w: record %d: burst duration must be positive, got %d", ErrInvalidProcesses, record, p.BurstDuration)
+	case p.ArrivalTime < 0:
+		return fmt.Errorf("%w: record %d: arrival time must not be negative, got %d", ErrInvalidProcesses, record, p.ArrivalTime)
+	case p.Memory < 0:
+		return fmt.Errorf("%w: record %d: memory must not be negative, got %d", ErrInvalidProcesses, record, p.Memory)
+	}
+	for _, burst := range p.History {
+		if burst <= 0 {
+			return fmt.Errorf("%w: record %d: prior bursts must be positive, got %d", ErrInvalidProcesses, record, burst)
+		}
+	}
+
+	return nil
+}
+
+//endregion
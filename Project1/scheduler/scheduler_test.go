@@ -0,0 +1,621 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestFCFSSchedule(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		title     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantOut string
+	}{
+		{
+			name: "default",
+			args: args{
+				processes: []Process{
+					{
+						ProcessID:     1,
+						ArrivalTime:   0,
+						BurstDuration: 5,
+						Priority:      2,
+					},
+					{
+						ProcessID:     2,
+						ArrivalTime:   3,
+						BurstDuration: 9,
+						Priority:      1,
+					},
+					{
+						ProcessID:     3,
+						ArrivalTime:   6,
+						BurstDuration: 6,
+						Priority:      3,
+					},
+				},
+				title: "First-come, First-serve",
+			},
+			wantOut: loadFixture(t, "fcfs_test.txt"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			FCFSSchedule(&w, tt.args.title, tt.args.processes)
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("FCFSSchedule() = %v, want %v", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_fcfs(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantGantt []TimeSlice
+		wantWaits []int64
+		wantIdle  int64
+	}{
+		{
+			name: "idle before a late arrival",
+			args: args{processes: []Process{
+				{ProcessID: 1, ArrivalTime: 2, BurstDuration: 3},
+				{ProcessID: 2, ArrivalTime: 10, BurstDuration: 4},
+			}},
+			wantGantt: []TimeSlice{
+				{Start: 0, Stop: 2, Idle: true},
+				{PID: 1, Start: 2, Stop: 5},
+				{Start: 5, Stop: 10, Idle: true},
+				{PID: 2, Start: 10, Stop: 14},
+			},
+			wantWaits: []int64{0, 0},
+			wantIdle:  7,
+		},
+		{
+			name: "out-of-order arrivals",
+			args: args{processes: []Process{
+				{ProcessID: 1, ArrivalTime: 4, BurstDuration: 2},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: 3, ArrivalTime: 4, BurstDuration: 1},
+			}},
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 5},
+				{PID: 1, Start: 5, Stop: 7},
+				{PID: 3, Start: 7, Stop: 8},
+			},
+			wantWaits: []int64{1, 0, 3},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := fcfs(tt.args.processes, memoryConfig{}, cpuModel{}, nil)
+			if !reflect.DeepEqual(res.Gantt, tt.wantGantt) {
+				t.Errorf("fcfs() Gantt = %v, want %v", res.Gantt, tt.wantGantt)
+			}
+			for i, want := range tt.wantWaits {
+				if res.Stats[i].Wait != want {
+					t.Errorf("process %d waits %d, want %d", res.Stats[i].ProcessID, res.Stats[i].Wait, want)
+				}
+			}
+			if got := res.idle(); got != tt.wantIdle {
+				t.Errorf("idle() = %d, want %d", got, tt.wantIdle)
+			}
+			if err := verifySchedule(tt.args.processes, res, true); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestRRSchedule checks that a process leaves the ready queue once it completes, rather than staying at its
+// head and being run again for no time, forever.
+func TestRRSchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, BurstDuration: 1},
+	}
+	done := make(chan string)
+	go func() {
+		var w bytes.Buffer
+		RRSchedule(&w, "Round-robin", processes, 2)
+		done <- w.String()
+	}()
+	var out string
+	select {
+	case out = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RRSchedule() didn't return, running a completed process again")
+	}
+	for _, want := range []string{
+		"|  1 |        0 |     3 |       0 |       1 |          4 |          4 |",
+		"|  2 |        0 |     1 |       0 |       2 |          3 |          3 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RRSchedule() = %v, want a row %v", out, want)
+		}
+	}
+}
+
+func TestParams_Set(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		set [][2]string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantQuantum int64
+		wantErr     error
+	}{
+		{
+			name:        "quantum",
+			args:        args{set: [][2]string{{"quantum", "3"}}},
+			wantQuantum: 3,
+		},
+		{
+			name:        "quantum before its unit",
+			args:        args{set: [][2]string{{"quantum", "1.5ms"}, {"unit", "ms"}, {"precision", "1"}}},
+			wantQuantum: 15,
+		},
+		{
+			name:    "unknown parameter",
+			args:    args{set: [][2]string{{"verify", "true"}}},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "not a number",
+			args:    args{set: [][2]string{{"cpus", "two"}}},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "invalid once parsed",
+			args:    args{set: [][2]string{{"quantum", "0"}}},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			var err error
+			for _, set := range tt.args.set {
+				if err = p.Set(set[0], set[1]); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = p.Parse()
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.timeQuantum != tt.wantQuantum {
+				t.Errorf("time quantum = %d, want %d", p.timeQuantum, tt.wantQuantum)
+			}
+		})
+	}
+}
+
+func TestPolicy_Schedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5, Memory: 60}, {ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Memory: 40}}
+	tests := []struct {
+		name    string
+		set     [2]string
+		wantErr error
+	}{
+		{name: "default", set: [2]string{"memory", "0"}},
+		{name: "memory fits", set: [2]string{"memory", "100"}},
+		{name: "process needs more memory than there is", set: [2]string{"memory", "50"}, wantErr: ErrInvalidArgs},
+		{name: "invalid frequency states", set: [2]string{"pstates", "50:2"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			if err := p.Set(tt.set[0], tt.set[1]); err != nil {
+				t.Fatal(err)
+			}
+			for _, pol := range Policies() {
+				res, err := pol.Schedule(processes, p)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%s: error = %v, want %v", pol.Name(), err, tt.wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if err := pol.Verify(processes, res); err != nil {
+					t.Errorf("%s: %v", pol.Name(), err)
+				}
+			}
+		})
+	}
+
+	// Processes that aren't loaded skip the load-time checks, so Schedule makes them
+	dependencies := []struct {
+		name      string
+		processes []Process
+	}{
+		{name: "cycle", processes: []Process{
+			{ProcessID: 1, BurstDuration: 2, DependsOn: []int64{2}},
+			{ProcessID: 2, BurstDuration: 2, DependsOn: []int64{1}},
+		}},
+		{name: "unknown process", processes: []Process{{ProcessID: 1, BurstDuration: 2, DependsOn: []int64{3}}}},
+	}
+	for _, tt := range dependencies {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, pol := range Policies() {
+				if _, err := pol.Schedule(tt.processes, DefaultParams()); !errors.Is(err, ErrInvalidProcesses) {
+					t.Errorf("%s: error = %v, want %v", pol.Name(), err, ErrInvalidProcesses)
+				}
+			}
+		})
+	}
+}
+
+func Test_loadProcesses(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		r io.Reader
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Process
+		wantErr error
+	}{
+		{
+			name: "bad CSV",
+			args: args{
+				r: iotest.ErrReader(io.ErrUnexpectedEOF),
+			},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name: "header",
+			args: args{
+				r: strings.NewReader(`ID,Arrival,Burst,Job
+1,0,5,10
+2,3,9,10
+3,6,6,0`),
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, JobID: 10},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, JobID: 10},
+				{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6},
+			},
+		},
+		{
+			name: "header unknown column",
+			args: args{
+				r: strings.NewReader("id,burst,arrival,color\n1,5,0,red"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "header duplicate column",
+			args: args{
+				r: strings.NewReader("id,burst,arrival,burst\n1,5,0,5"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "header missing column",
+			args: args{
+				r: strings.NewReader("id,burst\n1,5"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "header depends",
+			args: args{
+				r: strings.NewReader(`id,burst,arrival,depends
+1,5,0,
+2,9,3,1
+3,6,6,1;2`),
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, DependsOn: []int64{1}},
+				{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, DependsOn: []int64{1, 2}},
+			},
+		},
+		{
+			name: "depends on unknown process",
+			args: args{
+				r: strings.NewReader("id,burst,arrival,depends\n1,5,0,4"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "dependency cycle",
+			args: args{
+				r: strings.NewReader("id,burst,arrival,depends\n1,5,0,3\n2,9,3,1\n3,6,6,2"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "too few fields",
+			args: args{
+				r: strings.NewReader("1,5"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "not a number",
+			args: args{
+				r: strings.NewReader("1,five,0"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "duplicate process ID",
+			args: args{
+				r: strings.NewReader("1,5,0\n1,9,3"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "zero burst",
+			args: args{
+				r: strings.NewReader("1,0,0"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "negative arrival",
+			args: args{
+				r: strings.NewReader("1,5,-1"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "time overflows",
+			args: args{
+				r: strings.NewReader("1,5,9223372036854775805"),
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "success",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,9,3,1
+3,6,3,3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+				{
+					ProcessID:     3,
+					ArrivalTime:   3,
+					BurstDuration: 6,
+					Priority:      3,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := loadProcesses(tt.args.r)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadProcesses() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// maxFuzzWork bounds the total burst fuzzed through the schedulers, which run round-robin one quantum at a time.
+const maxFuzzWork = 1 << 12
+
+// addProcessesCorpus seeds a fuzz target with the example processes and every golden workload.
+func addProcessesCorpus(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join("testdata", "*", "processes.csv"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, seed := range append(seeds, filepath.Join("..", "example_processes.csv")) {
+		b, err := os.ReadFile(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+}
+
+func FuzzLoadProcesses(f *testing.F) {
+	addProcessesCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		processes, err := loadProcesses(bytes.NewReader(data))
+		if err != nil {
+			if processes != nil {
+				t.Errorf("loadProcesses() = %v with error %v, want no processes", processes, err)
+			}
+			return
+		}
+		seen := make(map[int64]bool, len(processes))
+		for _, p := range processes {
+			if seen[p.ProcessID] || p.BurstDuration <= 0 || p.ArrivalTime < 0 {
+				t.Errorf("loadProcesses() accepted invalid process %+v", p)
+			}
+			seen[p.ProcessID] = true
+		}
+	})
+}
+
+// FuzzSchedule runs any input that loads through every policy, checks the invariants and renders it.
+func FuzzSchedule(f *testing.F) {
+	addProcessesCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		processes, err := loadProcesses(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		var work int64
+		for _, p := range processes {
+			work += p.BurstDuration
+		}
+		if work > maxFuzzWork {
+			t.Skip("too many time quanta to simulate")
+		}
+		if err := defaultParams.cpu.validate(processes); err != nil {
+			return
+		}
+
+		for _, p := range policies {
+			res := p.schedule(processes, defaultParams)
+			if err := verifySchedule(processes, res, p.workConserving); err != nil {
+				t.Errorf("%s: %v", p.name, err)
+			}
+			outputResult(io.Discard, p.title, res)
+		}
+	})
+}
+
+// syntheticProcesses returns n processes with bursts of 1 to 16 arriving twice as fast as one CPU can run
+// them, so the ready queue grows to about half of them.
+func syntheticProcesses(n int) []Process {
+	r := rand.New(rand.NewSource(int64(n)))
+	processes := make([]Process, n)
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   int64(i) * 17 / 4,
+			BurstDuration: r.Int63n(16) + 1,
+			Priority:      r.Int63n(4) + 1,
+		}
+	}
+	return processes
+}
+
+func BenchmarkSchedule(b *testing.B) {
+	for _, n := range []int{1_000, 100_000, 1_000_000} {
+		processes := syntheticProcesses(n)
+		for _, p := range policies {
+			if p.name == "gang" {
+				continue
+			}
+			p := p
+			b.Run(fmt.Sprintf("%s/%d", p.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					p.schedule(processes, defaultParams)
+				}
+			})
+		}
+	}
+}
+
+// scanQueue is a ready queue that scans every process for the shortest, as the schedulers did before the
+// event-driven engine, to compare against.
+type scanQueue struct {
+	processes []Process
+	queue     []int
+}
+
+func (q *scanQueue) push(i int) { q.queue = append(q.queue, i) }
+
+func (q *scanQueue) pop() int {
+	shortest := 0
+	for j := range q.queue {
+		if q.processes[q.queue[j]].BurstDuration < q.processes[q.queue[shortest]].BurstDuration {
+			shortest = j
+		}
+	}
+	i := q.queue[shortest]
+	q.queue = append(q.queue[:shortest], q.queue[shortest+1:]...)
+	return i
+}
+
+func (q *scanQueue) len() int { return len(q.queue) }
+
+func (q *scanQueue) each(f func(i int)) {
+	for _, i := range q.queue {
+		f(i)
+	}
+}
+
+// BenchmarkReadyQueue compares SJF's heap against scanning the ready queue, pushing two processes for every
+// one popped like the synthetic workload, so the queue grows to half of them.
+func BenchmarkReadyQueue(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 50_000} {
+		processes := syntheticProcesses(n)
+		queues := map[string]func() readyQueue{
+			"heap": func() readyQueue {
+				return discipline{less: shorterBurst}.readyQueue(newAdmission(processes, memoryConfig{}), nil, nil)
+			},
+			"scan": func() readyQueue { return &scanQueue{processes: processes} },
+		}
+		for _, name := range []string{"heap", "scan"} {
+			newQueue := queues[name]
+			b.Run(fmt.Sprintf("%s/%d", name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					q := newQueue()
+					for j := range processes {
+						q.push(j)
+						if j%2 == 1 {
+							q.pop()
+						}
+					}
+					for q.len() > 0 {
+						q.pop()
+					}
+				}
+			})
+		}
+	}
+}
+
+func loadFixture(t *testing.T, p ...string) string {
+	b, err := os.ReadFile(path.Join(p...))
+	if err != nil {
+		t.Fail()
+	}
+
+	return string(b)
+}
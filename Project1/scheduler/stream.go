@@ -0,0 +1,251 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// processStream reads processes from a CSV scheduling file as the simulation reaches their arrival, holding
+// only the processes that have arrived and not yet completed, each in a slot that is reused once it completes.
+// The records must be in arrival order, and processes can't depend on each other.
+type processStream struct {
+	records *csv.Reader
+	columns []string
+	scale   timeScale
+	cpu     cpuModel
+	record  int
+	// next is the next process to arrive, read ahead of its arrival.
+	next    Process
+	hasNext bool
+	slots   []Process
+	free    []int
+	// live holds the IDs of the processes that have arrived and not yet completed.
+	live map[int64]bool
+	// end is the latest any process read so far can complete, at the slowest speed.
+	end int64
+	// err is the first error reading the processes, after which the stream ends.
+	err error
+}
+
+func newProcessStream(r io.Reader, scale timeScale, cpu cpuModel) *processStream {
+	records := csv.NewReader(r)
+	records.ReuseRecord = true
+	s := &processStream{records: records, scale: scale, cpu: cpu, live: make(map[int64]bool)}
+	s.read()
+
+	return s
+}
+
+// read reads the next process ahead of its arrival, unless the file or an error ends the stream.
+func (s *processStream) read() {
+	s.hasNext = false
+	for s.err == nil {
+		row, err := s.records.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			s.err = fmt.Errorf("%w: reading CSV", err)
+			return
+		}
+		// The file may start with a header row
+		if s.record == 0 && s.columns == nil && len(row) > 0 {
+			if _, err := strconv.ParseInt(row[0], 10, 64); err != nil {
+				s.columns, s.err = csvHeader(row)
+				continue
+			}
+		}
+
+		s.record++
+		var p Process
+		if s.err = parseRecord(&p, row, s.columns, s.scale, s.record); s.err != nil {
+			return
+		}
+		if s.err = s.validate(p); s.err != nil {
+			return
+		}
+		s.next, s.hasNext = p, true
+		return
+	}
+}
+
+// validate returns an error if a process read from the stream can't be scheduled.
+func (s *processStream) validate(p Process) error {
+	if err := validateProcess(p, s.record); err != nil {
+		return err
+	}
+	switch {
+	case len(p.DependsOn) > 0:
+		return fmt.Errorf("%w: record %d: process %d depends on other processes, which can't be streamed",
+			ErrInvalidProcesses, s.record, p.ProcessID)
+	case s.record > 1 && p.ArrivalTime < s.next.ArrivalTime:
+		return fmt.Errorf("%w: record %d: arrives at %s, before the record before it; streamed records must be in arrival order",
+			ErrInvalidProcesses, s.record, s.scale.format(p.ArrivalTime))
+	}
+
+	// Every process must be able to finish at the slowest speed without the clock overflowing
+	var (
+		work  = s.cpu.stretch(p.BurstDuration, int(maxi(0, int64(len(s.cpu.states)-1))))
+		start = maxi(s.end, p.ArrivalTime)
+	)
+	if work < 0 || work > math.MaxInt64-start {
+		return fmt.Errorf("%w: record %d: schedule would run past the largest representable time", ErrInvalidProcesses, s.record)
+	}
+	s.end = start + work
+
+	return nil
+}
+
+// admit returns the slots of the processes that have arrived by now, reading ahead to the next one.
+func (s *processStream) admit(now int64) []int {
+	admitted := make([]int, 0)
+	for s.hasNext && s.next.ArrivalTime <= now {
+		// An ID can be reused once its process has completed
+		if s.live[s.next.ProcessID] {
+			s.err = fmt.Errorf("%w: record %d: duplicate process ID %d", ErrInvalidProcesses, s.record, s.next.ProcessID)
+			s.hasNext = false
+			break
+		}
+		i := len(s.slots)
+		if n := len(s.free); n > 0 {
+			i, s.free = s.free[n-1], s.free[:n-1]
+			s.slots[i] = s.next
+		} else {
+			s.slots = append(s.slots, s.next)
+		}
+		s.live[s.next.ProcessID] = true
+		admitted = append(admitted, i)
+		s.read()
+	}
+
+	return admitted
+}
+
+// complete frees the slot of a completed process.
+func (s *processStream) complete(i int, _ int64) {
+	delete(s.live, s.slots[i].ProcessID)
+	s.slots[i] = Process{}
+	s.free = append(s.free, i)
+}
+
+func (s *processStream) arriving() bool {
+	return s.hasNext
+}
+
+func (s *processStream) nextArrival() int64 {
+	return s.next.ArrivalTime
+}
+
+func (s *processStream) process(i int) *Process {
+	return &s.slots[i]
+}
+
+// delay is always zero, as streamed processes are admitted as they arrive.
+func (s *processStream) delay(int) int64 {
+	return 0
+}
+
+type (
+	// streamPolicy is the first line of a streamed schedule, naming the policy and the unit of its times.
+	streamPolicy struct {
+		Type   string `json:"type"`
+		Policy string `json:"policy"`
+		Title  string `json:"title"`
+		Unit   string `json:"unit,omitempty"`
+		// TieBreak is the chain of keys that orders processes the policy finds equal.
+		TieBreak []string `json:"tieBreak,omitempty"`
+	}
+	// streamSlice is a line of a streamed schedule for each slice as it starts, and each time a process blocks
+	// on I/O.
+	streamSlice struct {
+		Type string `json:"type"`
+		sliceJSON
+	}
+	// streamExit is a line of a streamed schedule for each process, as it exits.
+	streamExit struct {
+		Type string `json:"type"`
+		processJSON
+	}
+	// streamSummary is the last line of a streamed schedule, with the averages of the processes and the
+	// energy used.
+	streamSummary struct {
+		Type string `json:"type"`
+		summaryJSON
+	}
+)
+
+// Stream schedules the processes of a CSV scheduling file read from r by the named policy as they arrive,
+// writing the schedule to w as JSON Lines; see streamSchedule.
+func Stream(w io.Writer, r io.Reader, policy string, p Params) error {
+	if err := p.Parse(); err != nil {
+		return err
+	}
+	return streamSchedule(w, r, policy, p)
+}
+
+// streamSchedule schedules the processes of a CSV scheduling file by the named policy as they are read,
+// writing the schedule to w as JSON Lines while it runs: a line naming the policy, a line for each slice as it
+// starts and for each process as it exits, and a summary, so the file never has to fit in memory.
+// Only policies that run on the discrete-event engine can be streamed, without modelling memory.
+func streamSchedule(w io.Writer, r io.Reader, name string, p Params) error {
+	pol, err := LookupPolicy(name)
+	if err != nil {
+		return err
+	}
+	switch {
+	case pol.stream == nil:
+		return fmt.Errorf("%w: the %s policy can't be streamed", ErrInvalidArgs, name)
+	case p.memory.capacity != 0:
+		return fmt.Errorf("%w: memory can't be modelled while streaming", ErrInvalidArgs)
+	}
+	if err := p.cpu.validate(nil); err != nil {
+		return err
+	}
+
+	var (
+		src   = newProcessStream(r, p.time, p.cpu)
+		bw    = bufio.NewWriter(w)
+		enc   = json.NewEncoder(bw)
+		ts    = p.time
+		meter = energyMeter{cpu: p.cpu, cpus: 1}
+		total struct {
+			processes        int
+			wait, turnaround float64
+			makespan         int64
+		}
+	)
+	// Write errors stick to bw, and are returned when it's flushed
+	_ = enc.Encode(streamPolicy{Type: "policy", Policy: pol.name, Title: pol.title, Unit: ts.unit, TieBreak: p.tieBreak})
+	pol.stream(p).simulate(src, 1, p.cpu,
+		func(slice TimeSlice) {
+			meter.add(slice)
+			_ = enc.Encode(streamSlice{Type: "slice", sliceJSON: ts.sliceJSON(slice)})
+		},
+		func(io TimeSlice) {
+			_ = enc.Encode(streamSlice{Type: "io", sliceJSON: ts.sliceJSON(io)})
+		},
+		func(_ int, s ProcessStats) {
+			total.processes++
+			total.wait += float64(s.Wait)
+			total.turnaround += float64(s.Turnaround)
+			total.makespan = maxi(total.makespan, s.Exit)
+			_ = enc.Encode(streamExit{Type: "exit", processJSON: ts.processJSON(s)})
+		})
+	// The records before a malformed line are still written, and its error takes precedence over a write error
+	if src.err != nil {
+		_ = bw.Flush()
+		return src.err
+	}
+	_ = enc.Encode(streamSummary{
+		Type:        "summary",
+		summaryJSON: ts.summaryJSON(total.processes, total.wait, total.turnaround, total.makespan, meter),
+	})
+
+	return bw.Flush()
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_streamSchedule(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		csv    string
+		policy string
+		memory int64
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantLines int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			args:      args{csv: "1,5,0\n2,3,1\n3,4,20", policy: "sjf"},
+			wantLines: 1 + 3 + 3 + 1,
+		},
+		{
+			name:      "header",
+			args:      args{csv: "id,arrival,burst\n1,0,5\n2,1,3", policy: "rr"},
+			wantLines: 1 + 5 + 2 + 1,
+		},
+		{
+			name:      "empty",
+			args:      args{policy: "dvfs"},
+			wantLines: 2,
+		},
+		{
+			name:    "unknown policy",
+			args:    args{csv: "1,5,0", policy: "lottery"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "policy that can't stream",
+			args:    args{csv: "1,5,0", policy: "gang"},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "memory",
+			args:    args{csv: "1,5,0", policy: "sjf", memory: 100},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:      "out of arrival order",
+			args:      args{csv: "1,5,3\n2,3,1", policy: "sjf"},
+			wantLines: 1 + 1 + 1,
+			wantErr:   ErrInvalidProcesses,
+		},
+		{
+			name:      "dependencies",
+			args:      args{csv: "id,burst,arrival,depends\n1,5,0,\n2,3,1,1", policy: "sjf"},
+			wantLines: 1 + 1 + 1,
+			wantErr:   ErrInvalidProcesses,
+		},
+		{
+			name:      "duplicate running process",
+			args:      args{csv: "1,5,0\n1,3,1", policy: "rr"},
+			wantLines: 1 + 3 + 1,
+			wantErr:   ErrInvalidProcesses,
+		},
+		{
+			name:      "ID reused after it exits",
+			args:      args{csv: "1,5,0\n1,3,10", policy: "rr"},
+			wantLines: 1 + 5 + 2 + 1,
+		},
+		{
+			name:      "bad record",
+			args:      args{csv: "1,5,0\n2,five,1", policy: "sjf"},
+			wantLines: 1 + 1 + 1,
+			wantErr:   ErrInvalidProcesses,
+		},
+		{
+			name:      "bad record after others",
+			args:      args{csv: "1,5,0\n2,3,10\n3,2,12\n4,four,20", policy: "rr"},
+			wantLines: 1 + 6 + 3,
+			wantErr:   ErrInvalidProcesses,
+		},
+		{
+			name:      "time overflows",
+			args:      args{csv: "1,5,9223372036854775805", policy: "sjf"},
+			wantLines: 1,
+			wantErr:   ErrInvalidProcesses,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			p.memory.capacity = tt.args.memory
+			var w bytes.Buffer
+			err := streamSchedule(&w, strings.NewReader(tt.args.csv), tt.args.policy, p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := strings.Count(w.String(), "\n"); got != tt.wantLines {
+				t.Errorf("streamSchedule() wrote %d lines, want %d:\n%s", got, tt.wantLines, w.String())
+			}
+		})
+	}
+}
+
+// TestStreamMatchesSchedule streams the golden workloads in arrival order through every policy that can
+// stream, checking the slices and exits against the schedule of the loaded processes.
+func TestStreamMatchesSchedule(t *testing.T) {
+	t.Parallel()
+	for _, workload := range []string{"basic", "contention", "fractional", "owners"} {
+		var (
+			dir       = filepath.Join("testdata", workload)
+			p         = loadGoldenParams(t, dir)
+			processes = loadGoldenProcesses(t, filepath.Join(dir, "processes.csv"), p.time)
+		)
+		for _, pol := range policies {
+			if pol.stream == nil {
+				continue
+			}
+			pol := pol
+			t.Run(workload+"/"+pol.name, func(t *testing.T) {
+				t.Parallel()
+				var w bytes.Buffer
+				if err := streamSchedule(&w, strings.NewReader(loadFixture(t, dir, "processes.csv")), pol.name, p); err != nil {
+					t.Fatal(err)
+				}
+
+				var (
+					res    = pol.schedule(processes, p)
+					ts     = p.time
+					slices []streamSlice
+					exits  = make(map[int64]streamExit)
+				)
+				lines := bufio.NewScanner(&w)
+				for lines.Scan() {
+					var line struct{ Type string }
+					if err := json.Unmarshal(lines.Bytes(), &line); err != nil {
+						t.Fatal(err)
+					}
+					switch line.Type {
+					case "slice":
+						var slice streamSlice
+						if err := json.Unmarshal(lines.Bytes(), &slice); err != nil {
+							t.Fatal(err)
+						}
+						slices = append(slices, slice)
+					case "exit":
+						var exit streamExit
+						if err := json.Unmarshal(lines.Bytes(), &exit); err != nil {
+							t.Fatal(err)
+						}
+						exits[exit.PID] = exit
+					}
+				}
+
+				want := make([]streamSlice, len(res.Gantt))
+				for i, slice := range res.Gantt {
+					want[i] = streamSlice{Type: "slice", sliceJSON: ts.sliceJSON(slice)}
+				}
+				if !reflect.DeepEqual(slices, want) {
+					t.Errorf("streamed slices = %v, want %v", slices, want)
+				}
+				for _, s := range res.Stats {
+					got, want := exits[s.ProcessID], fmt.Sprint(ts.format(s.Wait), ts.format(s.Exit))
+					if fmt.Sprint(got.Wait, got.Exit) != want {
+						t.Errorf("process %d streamed wait and exit %v %v, want %s", s.ProcessID, got.Wait, got.Exit, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_processStream(t *testing.T) {
+	t.Parallel()
+	// Each process exits before the next arrives, so the stream only ever holds one
+	var csv strings.Builder
+	for i := 0; i < 10_000; i++ {
+		fmt.Fprintf(&csv, "%d,%d,%d\n", i+1, i%16+1, i*20)
+	}
+	src := newProcessStream(strings.NewReader(csv.String()), timeScale{}, defaultParams.cpu)
+	var exits int
+	discipline{run: fullBurst}.simulate(src, 1, defaultParams.cpu, func(TimeSlice) {}, func(TimeSlice) {},
+		func(int, ProcessStats) { exits++ })
+	if src.err != nil {
+		t.Fatal(src.err)
+	}
+	if exits != 10_000 {
+		t.Errorf("%d processes exited, want 10000", exits)
+	}
+	if len(src.slots) != 1 || len(src.live) != 0 {
+		t.Errorf("stream held %d slots and %d live processes, want 1 and 0", len(src.slots), len(src.live))
+	}
+}
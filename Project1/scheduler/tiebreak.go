@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tieBreak is the chain of keys that orders processes a policy finds equal, e.g. two with the same burst under
+// SJF, or two admitted at once to a first-in, first-out ready queue: the first key that differs decides.
+// Processes that are equal by every key, or all of them with an empty chain, keep the order they were admitted in.
+// It is set as a flag of comma-separated keys, e.g. "arrival,pid".
+type tieBreak []string
+
+// tieBreakKeys compare two processes by each key of a tie-break chain, given their remaining work, returning
+// whether the first goes ahead of the second, after it or neither.
+var tieBreakKeys = map[string]func(a, b *Process, remainingA, remainingB int64) int{
+	"arrival": func(a, b *Process, _, _ int64) int { return compare(a.ArrivalTime, b.ArrivalTime) },
+	"pid":     func(a, b *Process, _, _ int64) int { return compare(a.ProcessID, b.ProcessID) },
+	// priority 1 is the highest
+	"priority":  func(a, b *Process, _, _ int64) int { return compare(a.Priority, b.Priority) },
+	"remaining": func(_, _ *Process, remainingA, remainingB int64) int { return compare(remainingA, remainingB) },
+}
+
+// compare returns -1 if a < b, 1 if a > b and 0 if they are equal.
+func compare(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (t *tieBreak) String() string {
+	return strings.Join(*t, ",")
+}
+
+func (t *tieBreak) Set(s string) error {
+	var keys tieBreak
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.ToLower(strings.TrimSpace(key)); key == "" {
+			continue
+		}
+		if _, ok := tieBreakKeys[key]; !ok {
+			return fmt.Errorf("unknown tie-break key %q, want arrival, pid, priority or remaining", key)
+		}
+		keys = append(keys, key)
+	}
+	*t = keys
+	return nil
+}
+
+// compare orders two processes with their remaining work by the chain.
+func (t tieBreak) compare(a, b *Process, remainingA, remainingB int64) int {
+	for _, key := range t {
+		if c := tieBreakKeys[key](a, b, remainingA, remainingB); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// sort orders processes admitted together, by their position in src, before any of them has run: in arrival
+// order, and processes that arrived together by the chain.
+func (t tieBreak) sort(admitted []int, src arrivals) {
+	if len(admitted) < 2 {
+		return
+	}
+	sort.SliceStable(admitted, func(i, j int) bool {
+		a, b := src.process(admitted[i]), src.process(admitted[j])
+		if a.ArrivalTime != b.ArrivalTime {
+			return a.ArrivalTime < b.ArrivalTime
+		}
+		return t.compare(a, b, a.BurstDuration, b.BurstDuration) < 0
+	})
+}
+
+// header describes the chain for the header of a report.
+func (t tieBreak) header() string {
+	return "Ties broken by " + strings.Join(t, ", then ")
+}
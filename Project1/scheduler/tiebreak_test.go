@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_tieBreak_Set(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   string
+		want    tieBreak
+		wantErr error
+	}{
+		{name: "chain", value: "Priority, remaining,pid", want: tieBreak{"priority", "remaining", "pid"}},
+		{name: "empty keeps admission order", value: ""},
+		{name: "unknown key", value: "arrival,burst", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := DefaultParams()
+			if err := p.Set("tie-break", tt.value); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Set() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(p.tieBreak, tt.want) {
+				t.Errorf("tieBreak = %v, want %v", p.tieBreak, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Schedule_tieBreak(t *testing.T) {
+	t.Parallel()
+	// Every process arrives together, and all but 4 have the same burst
+	processes := []Process{
+		{ProcessID: 3, BurstDuration: 2, Priority: 2},
+		{ProcessID: 1, BurstDuration: 2, Priority: 3},
+		{ProcessID: 4, BurstDuration: 1, Priority: 4},
+		{ProcessID: 2, BurstDuration: 2, Priority: 1},
+	}
+	tests := []struct {
+		name     string
+		policy   string
+		tieBreak string
+		want     []int64
+	}{
+		{name: "file order", policy: "fcfs", tieBreak: "", want: []int64{3, 1, 4, 2}},
+		{name: "arrival then pid", policy: "fcfs", tieBreak: "arrival,pid", want: []int64{1, 2, 3, 4}},
+		{name: "priority", policy: "fcfs", tieBreak: "priority", want: []int64{2, 3, 1, 4}},
+		{name: "remaining then pid", policy: "rr", tieBreak: "remaining,pid", want: []int64{4, 1, 2, 3}},
+		{name: "shortest burst first, then file order", policy: "sjf", tieBreak: "", want: []int64{4, 3, 1, 2}},
+		{name: "shortest burst first, then pid", policy: "sjf", tieBreak: "arrival,pid", want: []int64{4, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pol, err := LookupPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := DefaultParams()
+			if err := p.Set("tie-break", tt.tieBreak); err != nil {
+				t.Fatal(err)
+			}
+			res, err := pol.Schedule(processes, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, slice := range res.Gantt {
+				if !slice.Idle && (len(got) == 0 || got[len(got)-1] != slice.PID) {
+					got = append(got, slice.PID)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule() runs %v, want %v", got, tt.want)
+			}
+
+			var w bytes.Buffer
+			res.Render(&w, pol.Title())
+			if header := "Ties broken by "; strings.Contains(w.String(), header) != (len(res.TieBreak) > 0) {
+				t.Errorf("Render() = %s, want the tie-break chain %v in its header", w.String(), res.TieBreak)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// State is the state of a process in its lifecycle.
+type State string
+
+const (
+	// StateNew processes have arrived and wait to be admitted to the ready queue, for memory or the processes
+	// they depend on.
+	StateNew        State = "new"
+	StateReady      State = "ready"
+	StateRunning    State = "running"
+	StateBlocked    State = "blocked"
+	StateTerminated State = "terminated"
+)
+
+type (
+	// Transition is a process changing state; the first transition of a process is from no state to new, as it
+	// arrives.
+	Transition struct {
+		At       int64
+		From, To State
+	}
+	// ProcessTimeline is every state transition of a process in a schedule, how long it spent in each state
+	// before it terminated, how often it was preempted and how long it waited to first run.
+	ProcessTimeline struct {
+		PID         int64
+		Transitions []Transition
+		Time        map[State]int64
+		Preemptions int
+		Response    int64
+	}
+)
+
+// Timelines returns the timeline of every process, in the same order as their timing, from its arrival,
+// admission, slices, I/O and exit.
+func (r Result) Timelines() []ProcessTimeline {
+	type interval struct {
+		TimeSlice
+		io bool
+	}
+	intervals := make(map[int64][]interval, len(r.Stats))
+	for _, slice := range r.Gantt {
+		if !slice.Idle {
+			intervals[slice.PID] = append(intervals[slice.PID], interval{TimeSlice: slice})
+		}
+	}
+	for _, slice := range r.IO {
+		intervals[slice.PID] = append(intervals[slice.PID], interval{TimeSlice: slice, io: true})
+	}
+
+	timelines := make([]ProcessTimeline, len(r.Stats))
+	for i, s := range r.Stats {
+		t := &timelines[i]
+		*t = ProcessTimeline{
+			PID:         s.ProcessID,
+			Transitions: []Transition{{At: s.ArrivalTime, To: StateNew}},
+			Time:        make(map[State]int64),
+		}
+		state := StateNew
+		move := func(at int64, to State) {
+			if to == state {
+				return
+			}
+			from := t.Transitions[len(t.Transitions)-1]
+			t.Time[state] += at - from.At
+			if state == StateRunning && to == StateReady {
+				t.Preemptions++
+			}
+			t.Transitions = append(t.Transitions, Transition{At: at, From: state, To: to})
+			state = to
+		}
+		move(s.ArrivalTime+s.Admission, StateReady)
+
+		// A process runs on until it blocks, or another slice of it starts later
+		own := intervals[s.ProcessID]
+		sort.SliceStable(own, func(a, b int) bool { return own[a].Start < own[b].Start })
+		var stop int64
+		for _, in := range own {
+			switch {
+			case in.io:
+				move(in.Start, StateBlocked)
+				move(in.Stop, StateReady)
+			case state == StateRunning && in.Start > stop:
+				move(stop, StateReady)
+				fallthrough
+			default:
+				move(in.Start, StateRunning)
+			}
+			stop = in.Stop
+		}
+		move(s.Exit, StateTerminated)
+		for _, tr := range t.Transitions {
+			if tr.To == StateRunning {
+				t.Response = tr.At - s.ArrivalTime
+				break
+			}
+		}
+	}
+
+	return timelines
+}
+
+// timelineSection tables how long each process spent in each state, how often it was preempted and how long it
+// waited to first run, followed by every state transition of each process.
+func (r Result) timelineSection() reportSection {
+	var (
+		ts        = r.Time
+		timelines = r.Timelines()
+		section   = reportSection{title: "Process timeline", table: &scheduleTable{
+			header: []string{"ID", ts.label("New"), ts.label("Ready"), ts.label("Running"), ts.label("Blocked"), "Preemptions", ts.label("Response")},
+		}}
+	)
+	for _, t := range timelines {
+		section.table.rows = append(section.table.rows, []string{
+			fmt.Sprint(t.PID),
+			ts.format(t.Time[StateNew]),
+			ts.format(t.Time[StateReady]),
+			ts.format(t.Time[StateRunning]),
+			ts.format(t.Time[StateBlocked]),
+			fmt.Sprint(t.Preemptions),
+			ts.format(t.Response),
+		})
+	}
+	for _, t := range timelines {
+		steps := make([]string, len(t.Transitions))
+		for i, tr := range t.Transitions {
+			steps[i] = fmt.Sprintf("%s %s", tr.To, ts.format(tr.At))
+		}
+		section.lines = append(section.lines, fmt.Sprintf("%d: %s", t.PID, strings.Join(steps, " → ")))
+	}
+	return section
+}
+
+// TimelineCSVHeader returns the header row of the records TimelineCSV returns.
+func TimelineCSVHeader() []string {
+	return []string{"policy", "pid", "time", "from", "to", "duration"}
+}
+
+// TimelineCSV returns a CSV record of each state transition of the processes of a schedule by a policy, with the
+// time the process spent in the state it left; times are on the schedule's time scale.
+func (r Result) TimelineCSV(policy string) [][]string {
+	var records [][]string
+	for _, t := range r.Timelines() {
+		for i, tr := range t.Transitions {
+			var duration int64
+			if i > 0 {
+				duration = tr.At - t.Transitions[i-1].At
+			}
+			records = append(records, []string{
+				policy, fmt.Sprint(t.PID), r.Time.format(tr.At), string(tr.From), string(tr.To), r.Time.format(duration),
+			})
+		}
+	}
+
+	return records
+}
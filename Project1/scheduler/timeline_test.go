@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResult_Timelines(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		res  Result
+		want []ProcessTimeline
+	}{
+		{
+			name: "preempted",
+			res: Result{
+				Gantt: []TimeSlice{
+					{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 3}, {PID: 2, Start: 3, Stop: 5},
+					{PID: 1, Start: 5, Stop: 6},
+				},
+				Stats: []ProcessStats{
+					{Process: Process{ProcessID: 1, BurstDuration: 4}, Wait: 2, Turnaround: 6, Exit: 6},
+					{Process: Process{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2}, Wait: 2, Turnaround: 4, Exit: 5},
+				},
+			},
+			want: []ProcessTimeline{
+				{
+					PID: 1,
+					Transitions: []Transition{
+						{At: 0, To: StateNew}, {At: 0, From: StateNew, To: StateReady},
+						{At: 0, From: StateReady, To: StateRunning}, {At: 3, From: StateRunning, To: StateReady},
+						{At: 5, From: StateReady, To: StateRunning}, {At: 6, From: StateRunning, To: StateTerminated},
+					},
+					Time:        map[State]int64{StateNew: 0, StateReady: 2, StateRunning: 4},
+					Preemptions: 1,
+				},
+				{
+					PID: 2,
+					Transitions: []Transition{
+						{At: 1, To: StateNew}, {At: 1, From: StateNew, To: StateReady},
+						{At: 3, From: StateReady, To: StateRunning}, {At: 5, From: StateRunning, To: StateTerminated},
+					},
+					Time:     map[State]int64{StateNew: 0, StateReady: 2, StateRunning: 2},
+					Response: 2,
+				},
+			},
+		},
+		{
+			name: "admitted late and blocked on I/O",
+			res: Result{
+				Gantt: []TimeSlice{{Start: 0, Stop: 2, Idle: true}, {PID: 1, Start: 2, Stop: 3}, {PID: 1, Start: 5, Stop: 6}},
+				IO:    []TimeSlice{{PID: 1, Start: 3, Stop: 5}},
+				Stats: []ProcessStats{
+					{Process: Process{ProcessID: 1, BurstDuration: 2}, Admission: 2, Wait: 2, IO: 2, Turnaround: 6, Exit: 6},
+				},
+			},
+			want: []ProcessTimeline{
+				{
+					PID: 1,
+					Transitions: []Transition{
+						{At: 0, To: StateNew}, {At: 2, From: StateNew, To: StateReady},
+						{At: 2, From: StateReady, To: StateRunning}, {At: 3, From: StateRunning, To: StateBlocked},
+						{At: 5, From: StateBlocked, To: StateReady}, {At: 5, From: StateReady, To: StateRunning},
+						{At: 6, From: StateRunning, To: StateTerminated},
+					},
+					Time:     map[State]int64{StateNew: 2, StateReady: 0, StateRunning: 2, StateBlocked: 2},
+					Response: 2,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.res.Timelines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Timelines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_TimelineCSV(t *testing.T) {
+	t.Parallel()
+	res := Result{
+		Gantt: []TimeSlice{{PID: 7, Start: 10, Stop: 25}},
+		Stats: []ProcessStats{{Process: Process{ProcessID: 7, ArrivalTime: 5, BurstDuration: 15}, Wait: 5, Turnaround: 20, Exit: 25}},
+		Time:  timeScale{unit: "ms", precision: 1},
+	}
+	want := [][]string{
+		{"rr", "7", "0.5", "", "new", "0"},
+		{"rr", "7", "0.5", "new", "ready", "0"},
+		{"rr", "7", "1", "ready", "running", "0.5"},
+		{"rr", "7", "2.5", "running", "terminated", "1.5"},
+	}
+	if got := res.TimelineCSV("rr"); !reflect.DeepEqual(got, want) {
+		t.Errorf("TimelineCSV() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeUnits are the units times can be read and written in.
+var timeUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// timeScale is how times are read and written. The scheduler counts time in whole ticks,
+// each a fraction of a unit of time with a number of decimal places.
+type timeScale struct {
+	// unit names the unit of time, e.g. "ms"; empty means times are unitless.
+	unit string
+	// precision is the number of decimal places of a unit that times are kept to.
+	precision int
+	// tick is the duration of one tick when times are unitless, for traces recorded in wall-clock time.
+	tick time.Duration
+}
+
+// validate returns an error if times can't be kept to the scale.
+func (s timeScale) validate() error {
+	if s.precision < 0 || s.precision > 9 {
+		return fmt.Errorf("%w: precision must be 0 to 9 decimal places, got %d", ErrInvalidArgs, s.precision)
+	}
+	if s.unit == "" {
+		return nil
+	}
+	unit, ok := timeUnits[s.unit]
+	if !ok {
+		return fmt.Errorf("%w: unknown time unit %q", ErrInvalidArgs, s.unit)
+	}
+	if unit%time.Duration(s.perUnit()) != 0 {
+		return fmt.Errorf("%w: %d decimal places of %s is finer than a nanosecond", ErrInvalidArgs, s.precision, s.unit)
+	}
+
+	return nil
+}
+
+// perUnit returns the number of ticks in a unit of time.
+func (s timeScale) perUnit() int64 {
+	n := int64(1)
+	for i := 0; i < s.precision; i++ {
+		n *= 10
+	}
+	return n
+}
+
+// tickDuration returns the duration of one tick.
+func (s timeScale) tickDuration() time.Duration {
+	if s.unit == "" {
+		return s.tick
+	}
+	return timeUnits[s.unit] / time.Duration(s.perUnit())
+}
+
+// parse returns the ticks in a time, which is either a decimal number of units such as "1.5"
+// or, when times have a unit, a duration such as "1.5ms" or "300us".
+func (s timeScale) parse(field string) (int64, error) {
+	field = strings.TrimSpace(field)
+	if strings.IndexFunc(field, func(r rune) bool { return r >= 'a' && r <= 'z' || r == 'µ' }) >= 0 {
+		return s.parseDuration(field)
+	}
+
+	// Shift the decimal point right by the precision
+	whole, fraction, _ := strings.Cut(field, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid time %q", field)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > s.precision {
+		return 0, fmt.Errorf("time %q has more than %d decimal places", field, s.precision)
+	}
+	ticks, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", s.precision-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", field)
+	}
+
+	return ticks, nil
+}
+
+func (s timeScale) parseDuration(field string) (int64, error) {
+	d, err := time.ParseDuration(field)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", field)
+	}
+	if s.unit == "" {
+		return 0, fmt.Errorf("time %q has a unit, but times are unitless", field)
+	}
+	ticks, rem := new(big.Int).QuoRem(
+		new(big.Int).Mul(big.NewInt(int64(d)), big.NewInt(s.perUnit())),
+		big.NewInt(int64(timeUnits[s.unit])),
+		new(big.Int))
+	if rem.Sign() != 0 {
+		return 0, fmt.Errorf("time %q has more than %d decimal places of %s", field, s.precision, s.unit)
+	}
+	if !ticks.IsInt64() {
+		return 0, fmt.Errorf("time %q is out of range", field)
+	}
+
+	return ticks.Int64(), nil
+}
+
+// format returns a number of ticks as a decimal number of units.
+func (s timeScale) format(ticks int64) string {
+	perUnit := s.perUnit()
+	if perUnit == 1 {
+		return strconv.FormatInt(ticks, 10)
+	}
+	if ticks < 0 && ticks != math.MinInt64 {
+		return "-" + s.format(-ticks)
+	}
+	whole, fraction := ticks/perUnit, ticks%perUnit
+	if fraction == 0 {
+		return strconv.FormatInt(whole, 10)
+	}
+	return fmt.Sprintf("%d.%s", whole, strings.TrimRight(fmt.Sprintf("%0*d", s.precision, fraction), "0"))
+}
+
+// formatAverage returns an average number of ticks as a number of units with at least two decimal places.
+func (s timeScale) formatAverage(ticks float64) string {
+	return strconv.FormatFloat(ticks/float64(s.perUnit()), 'f', int(maxi(2, int64(s.precision))), 64)
+}
+
+// label returns a column name with the unit its times are in, if any.
+func (s timeScale) label(name string) string {
+	if s.unit == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, s.unit)
+}
+
+// formatRate returns a rate per tick as a rate per unit of time, to two decimal places or, if it's smaller,
+// two significant figures.
+func (s timeScale) formatRate(perTick float64) string {
+	unit := s.unit
+	if unit == "" {
+		unit = "t"
+	}
+	perUnit := perTick * float64(s.perUnit())
+	if perUnit > 0 && perUnit < 0.01 {
+		return fmt.Sprintf("%.2g/%s", perUnit, unit)
+	}
+	return fmt.Sprintf("%.2f/%s", perUnit, unit)
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_timeScale_parse(t *testing.T) {
+	t.Parallel()
+	ms := timeScale{unit: "ms", precision: 1}
+	tests := []struct {
+		name    string
+		scale   timeScale
+		field   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "integer", field: "42", want: 42},
+		{name: "unitless decimal", scale: timeScale{precision: 2}, field: "1.5", want: 150},
+		{name: "leading point", scale: timeScale{precision: 2}, field: ".25", want: 25},
+		{name: "trailing zeros", field: "3.000", want: 3},
+		{name: "too precise", scale: timeScale{precision: 1}, field: "1.25", wantErr: true},
+		{name: "decimal in unit", scale: ms, field: "2.5", want: 25},
+		{name: "duration", scale: ms, field: "1.5ms", want: 15},
+		{name: "duration in another unit", scale: ms, field: "300us", want: 3},
+		{name: "duration too precise", scale: ms, field: "10us", wantErr: true},
+		{name: "duration while unitless", field: "1ms", wantErr: true},
+		{name: "large duration", scale: timeScale{unit: "ns", precision: 0}, field: "2562047h", want: 2562047 * 3600e9},
+		{name: "not a time", field: "five", wantErr: true},
+		{name: "just a point", field: ".", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.scale.parse(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse(%q) error = %v, wantErr %v", tt.field, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parse(%q) = %d, want %d", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_timeScale_format(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		scale timeScale
+		ticks int64
+		want  string
+	}{
+		{name: "ticks", ticks: 42, want: "42"},
+		{name: "whole units", scale: timeScale{precision: 3}, ticks: 2000, want: "2"},
+		{name: "fraction", scale: timeScale{precision: 3}, ticks: 2050, want: "2.05"},
+		{name: "under a unit", scale: timeScale{precision: 3}, ticks: 7, want: "0.007"},
+		{name: "negative", scale: timeScale{precision: 1}, ticks: -15, want: "-1.5"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.scale.format(tt.ticks); got != tt.want {
+				t.Errorf("format(%d) = %q, want %q", tt.ticks, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_params_parse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		params  Params
+		want    int64
+		wantErr error
+	}{
+		{
+			name:   "quantum in units",
+			params: Params{quantum: "2", cpus: 1, weights: 1, time: timeScale{unit: "ms", precision: 1}},
+			want:   20,
+		},
+		{
+			name:   "quantum as a duration",
+			params: Params{quantum: "500us", cpus: 1, weights: 1, time: timeScale{unit: "ms", precision: 1}},
+			want:   5,
+		},
+		{
+			name:    "unknown unit",
+			params:  Params{quantum: "2", cpus: 1, time: timeScale{unit: "fortnight"}},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "finer than a nanosecond",
+			params:  Params{quantum: "2", cpus: 1, time: timeScale{unit: "ns", precision: 1}},
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "quantum too precise",
+			params:  Params{quantum: "0.25", cpus: 1, time: timeScale{precision: 1}},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := tt.params
+			if err := p.Parse(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && p.timeQuantum != tt.want {
+				t.Errorf("parse() quantum = %d ticks, want %d", p.timeQuantum, tt.want)
+			}
+		})
+	}
+}
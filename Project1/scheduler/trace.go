@@ -0,0 +1,301 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inputFormats reads processes from each supported scheduling file format, keyed by the -input flag,
+// with times on a time scale.
+var inputFormats = map[string]func(r io.Reader, scale timeScale) ([]Process, error){
+	"csv":      loadScaledProcesses,
+	"json":     loadJSONTrace,
+	"procstat": loadProcStatTrace,
+	"perf": func(r io.Reader, scale timeScale) ([]Process, error) {
+		return loadPerfSchedTrace(r, scale.tickDuration())
+	},
+}
+
+// Load reads processes from a scheduling file in one of the input formats, "csv", "json", "procstat" or "perf",
+// with times on the time scale of p, returning an error for any process that can't be scheduled.
+func Load(r io.Reader, format string, p Params) ([]Process, error) {
+	load, ok := inputFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown input format %q", ErrInvalidArgs, format)
+	}
+	if err := p.Parse(); err != nil {
+		return nil, err
+	}
+
+	return load(r, p.time)
+}
+
+// loadJSONTrace reads processes from a JSON array of
+//
+//	{"pid": 1, "arrival": 0, "burst": 5, "priority": 2, "job": 10, "owner": "alice", "depends": [3, 5]}
+//
+// objects, where priority, job, owner and depends are optional and any other fields are ignored.
+// Times are numbers or strings on a time scale, e.g. 1.5 or "1.5ms".
+func loadJSONTrace(r io.Reader, scale timeScale) ([]Process, error) {
+	var records []struct {
+		PID      *int64          `json:"pid"`
+		Arrival  json.RawMessage `json:"arrival"`
+		Burst    json.RawMessage `json:"burst"`
+		Priority int64           `json:"priority"`
+		Job      int64           `json:"job"`
+		Owner    string          `json:"owner"`
+		Depends  []int64         `json:"depends"`
+	}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("%w: reading JSON trace: %v", ErrInvalidProcesses, err)
+	}
+
+	processes := make([]Process, len(records))
+	for i, rec := range records {
+		if rec.PID == nil || rec.Arrival == nil || rec.Burst == nil {
+			return nil, fmt.Errorf("%w: record %d: pid, arrival and burst are required", ErrInvalidProcesses, i+1)
+		}
+		var times [2]int64 // arrival and burst
+		for j, raw := range []json.RawMessage{rec.Arrival, rec.Burst} {
+			var field string
+			if err := json.Unmarshal(raw, &field); err != nil {
+				field = string(raw)
+			}
+			t, err := scale.parse(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidProcesses, i+1, err)
+			}
+			times[j] = t
+		}
+		processes[i] = Process{
+			ProcessID:     *rec.PID,
+			ArrivalTime:   times[0],
+			BurstDuration: times[1],
+			Priority:      rec.Priority,
+			JobID:         rec.Job,
+			Owner:         rec.Owner,
+			DependsOn:     rec.Depends,
+		}
+	}
+	if err := validateProcesses(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+// loadProcStatTrace reads processes from repeated samples of /proc/<pid>/stat, one sample per line, e.g. from
+//
+//	while sleep 1; do cat /proc/[0-9]*/stat; done
+//
+// Times are in clock ticks, taken as units of the time scale: each process arrives at its start time relative to
+// the earliest sampled process, bursts for the most CPU time (user + system) sampled, and keeps the kernel's
+// priority. Processes that never used any CPU time are dropped.
+func loadProcStatTrace(r io.Reader, scale timeScale) ([]Process, error) {
+	var (
+		byPID     = make(map[int64]*Process)
+		started   = make(map[int64]int64)
+		order     []int64
+		firstTick int64 = math.MaxInt64
+		scanner         = bufio.NewScanner(r)
+		line      int
+	)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		// The command name is in parentheses and may itself contain spaces and parentheses.
+		open, closing := strings.IndexByte(text, '('), strings.LastIndexByte(text, ')')
+		if open < 0 || closing < open {
+			return nil, fmt.Errorf("%w: line %d: no command name in /proc stat sample", ErrInvalidProcesses, line)
+		}
+		pid, err := strconv.ParseInt(strings.TrimSpace(text[:open]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidProcesses, line, err)
+		}
+		// fields[0] is the state, the third field of /proc/<pid>/stat.
+		fields := strings.Fields(text[closing+1:])
+		if len(fields) < 20 {
+			return nil, fmt.Errorf("%w: line %d: want at least 22 fields in /proc stat sample, got %d",
+				ErrInvalidProcesses, line, len(fields)+2)
+		}
+		var stat [4]int64 // utime, stime, priority and starttime
+		for i, field := range []int{14, 15, 18, 22} {
+			if stat[i], err = strconv.ParseInt(fields[field-3], 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: line %d: field %d: %v", ErrInvalidProcesses, line, field, err)
+			}
+		}
+		utime, stime, priority, starttime := stat[0], stat[1], stat[2], stat[3]
+
+		p, ok := byPID[pid]
+		if !ok {
+			p = &Process{ProcessID: pid}
+			byPID[pid], started[pid] = p, starttime
+			order = append(order, pid)
+		}
+		if started[pid] != starttime {
+			return nil, fmt.Errorf("%w: line %d: pid %d was reused by a new process", ErrInvalidProcesses, line, pid)
+		}
+		p.BurstDuration = maxi(p.BurstDuration, utime+stime)
+		p.Priority = priority
+		firstTick = mini(firstTick, starttime)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading /proc stat samples", err)
+	}
+
+	processes := make([]Process, 0, len(order))
+	perUnit := scale.perUnit()
+	for _, pid := range order {
+		if p := byPID[pid]; p.BurstDuration > 0 {
+			p.ArrivalTime = started[pid] - firstTick
+			if p.ArrivalTime > math.MaxInt64/perUnit || p.BurstDuration > math.MaxInt64/perUnit {
+				return nil, fmt.Errorf("%w: times of pid %d are out of range", ErrInvalidProcesses, pid)
+			}
+			p.ArrivalTime *= perUnit
+			p.BurstDuration *= perUnit
+			processes = append(processes, *p)
+		}
+	}
+	sortByArrival(processes)
+	if err := validateProcesses(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+// loadPerfSchedTrace reads threads as processes from `perf sched timehist` output in ticks of a duration,
+// whose lines are
+//
+//	time  [cpu]  task[tid/pid]  wait time  sch delay  run time
+//
+// with times in seconds and the last three columns in milliseconds.
+// Each thread arrives when it first became runnable and bursts for its total run time, both in ticks,
+// and belongs to the job of its process.
+// Idle tasks and threads that ran for less than half a tick are dropped.
+func loadPerfSchedTrace(r io.Reader, tick time.Duration) ([]Process, error) {
+	if tick <= 0 {
+		return nil, fmt.Errorf("%w: tick must be positive, got %v", ErrInvalidArgs, tick)
+	}
+
+	var (
+		runnable = make(map[int64]time.Duration)
+		job      = make(map[int64]int64)
+		ran      = make(map[int64]time.Duration)
+		order    []int64
+		first    time.Duration = math.MaxInt64
+		scanner                = bufio.NewScanner(r)
+		line     int
+	)
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		// Skip the column headers and separators.
+		if len(fields) < 6 || !strings.HasPrefix(fields[1], "[") {
+			continue
+		}
+		if _, err := strconv.ParseFloat(fields[0], 64); err != nil {
+			continue
+		}
+
+		// The task name may contain spaces, so the numeric columns are read from both ends.
+		task := strings.Join(fields[2:len(fields)-3], " ")
+		if strings.HasPrefix(task, "<idle>") {
+			continue
+		}
+		tid, pid, err := perfTaskID(task)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidProcesses, line, err)
+		}
+		var times [3]time.Duration // timestamp, scheduling delay and run time
+		for i, field := range []struct {
+			value string
+			unit  time.Duration
+		}{
+			{fields[0], time.Second},
+			{fields[len(fields)-2], time.Millisecond},
+			{fields[len(fields)-1], time.Millisecond},
+		} {
+			v, err := strconv.ParseFloat(field.value, 64)
+			if err != nil || v < 0 || v*float64(field.unit) > math.MaxInt64 {
+				return nil, fmt.Errorf("%w: line %d: invalid time %q", ErrInvalidProcesses, line, field.value)
+			}
+			times[i] = time.Duration(v * float64(field.unit))
+		}
+		timestamp, delay, run := times[0], times[1], times[2]
+
+		if _, ok := ran[tid]; !ok {
+			runnable[tid] = timestamp - run - delay
+			job[tid] = pid
+			order = append(order, tid)
+			if runnable[tid] < first {
+				first = runnable[tid]
+			}
+		}
+		ran[tid] += run
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading perf sched trace", err)
+	}
+
+	processes := make([]Process, 0, len(order))
+	for _, tid := range order {
+		p := Process{
+			ProcessID:     tid,
+			ArrivalTime:   int64(math.Round(float64(runnable[tid]-first) / float64(tick))),
+			BurstDuration: int64(math.Round(float64(ran[tid]) / float64(tick))),
+			JobID:         job[tid],
+		}
+		if p.BurstDuration > 0 {
+			processes = append(processes, p)
+		}
+	}
+	sortByArrival(processes)
+	if err := validateProcesses(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+// perfTaskID returns the thread and process IDs of a perf task name such as "gcc[31951/31949]",
+// or "gcc[31949]" for a process's main thread.
+func perfTaskID(task string) (tid, pid int64, err error) {
+	open, closing := strings.LastIndexByte(task, '['), strings.LastIndexByte(task, ']')
+	if open < 0 || closing < open {
+		return 0, 0, fmt.Errorf("no thread ID in task %q", task)
+	}
+	ids := strings.SplitN(task[open+1:closing], "/", 2)
+	if tid, err = strconv.ParseInt(ids[0], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if len(ids) == 1 {
+		return tid, tid, nil
+	}
+	if pid, err = strconv.ParseInt(ids[1], 10, 64); err != nil {
+		return 0, 0, err
+	}
+
+	return tid, pid, nil
+}
+
+// sortByArrival sorts imported processes by arrival time, then by process ID.
+func sortByArrival(processes []Process) {
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].ArrivalTime != processes[j].ArrivalTime {
+			return processes[i].ArrivalTime < processes[j].ArrivalTime
+		}
+		return processes[i].ProcessID < processes[j].ProcessID
+	})
+}
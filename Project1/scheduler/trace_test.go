@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTrace(t *testing.T, name string) io.Reader {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "traces", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+
+	return f
+}
+
+func Test_inputFormats(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		format string
+		r      func(t *testing.T) io.Reader
+		scale  timeScale
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Process
+		wantErr error
+	}{
+		{
+			name: "json",
+			args: args{
+				format: "json",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "processes.json") },
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1, JobID: 1},
+				{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, DependsOn: []int64{1, 2}},
+			},
+		},
+		{
+			name: "json times on a scale",
+			args: args{
+				format: "json",
+				r: func(*testing.T) io.Reader {
+					return strings.NewReader(`[{"pid": 1, "arrival": 0.5, "burst": "1.5ms"}, {"pid": 2, "arrival": "2ms", "burst": 3}]`)
+				},
+				scale: timeScale{unit: "ms", precision: 1},
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 5, BurstDuration: 15},
+				{ProcessID: 2, ArrivalTime: 20, BurstDuration: 30},
+			},
+		},
+		{
+			name: "perf ticks of the unit",
+			args: args{
+				format: "perf",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "perf_timehist.txt") },
+				scale:  timeScale{unit: "ms", precision: 0, tick: time.Hour},
+			},
+			want: []Process{
+				{ProcessID: 1201, ArrivalTime: 0, BurstDuration: 5, JobID: 1201},
+				{ProcessID: 1202, ArrivalTime: 2, BurstDuration: 9, JobID: 1201},
+				{ProcessID: 1204, ArrivalTime: 10, BurstDuration: 6, JobID: 1204},
+			},
+		},
+		{
+			name: "json missing burst",
+			args: args{
+				format: "json",
+				r:      func(*testing.T) io.Reader { return strings.NewReader(`[{"pid": 1, "arrival": 0}]`) },
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "json not an array",
+			args: args{
+				format: "json",
+				r:      func(*testing.T) io.Reader { return strings.NewReader(`{"pid": 1}`) },
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "procstat",
+			args: args{
+				format: "procstat",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "procstat.txt") },
+			},
+			want: []Process{
+				{ProcessID: 1201, ArrivalTime: 0, BurstDuration: 5, Priority: 20},
+				{ProcessID: 1202, ArrivalTime: 3, BurstDuration: 9, Priority: 20},
+				{ProcessID: 1204, ArrivalTime: 6, BurstDuration: 6, Priority: 25},
+			},
+		},
+		{
+			name: "procstat reused pid",
+			args: args{
+				format: "procstat",
+				r: func(*testing.T) io.Reader {
+					return strings.NewReader("7 (a) R 1 1 1 0 1 0 0 0 0 0 1 0 0 0 20 0 1 0 10 0 0\n" +
+						"7 (b) R 1 1 1 0 1 0 0 0 0 0 1 0 0 0 20 0 1 0 99 0 0\n")
+				},
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "procstat truncated",
+			args: args{
+				format: "procstat",
+				r:      func(*testing.T) io.Reader { return strings.NewReader("7 (a) R 1 1 1\n") },
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "perf",
+			args: args{
+				format: "perf",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "perf_timehist.txt") },
+				scale:  timeScale{tick: time.Millisecond},
+			},
+			want: []Process{
+				{ProcessID: 1201, ArrivalTime: 0, BurstDuration: 5, JobID: 1201},
+				{ProcessID: 1202, ArrivalTime: 2, BurstDuration: 9, JobID: 1201},
+				{ProcessID: 1204, ArrivalTime: 10, BurstDuration: 6, JobID: 1204},
+			},
+		},
+		{
+			name: "perf coarser tick",
+			args: args{
+				format: "perf",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "perf_timehist.txt") },
+				scale:  timeScale{tick: 2 * time.Millisecond},
+			},
+			want: []Process{
+				{ProcessID: 1201, ArrivalTime: 0, BurstDuration: 3, JobID: 1201},
+				{ProcessID: 1202, ArrivalTime: 1, BurstDuration: 5, JobID: 1201},
+				{ProcessID: 1204, ArrivalTime: 5, BurstDuration: 3, JobID: 1204},
+			},
+		},
+		{
+			name: "perf bad task",
+			args: args{
+				format: "perf",
+				r:      func(*testing.T) io.Reader { return strings.NewReader("1.0 [0000] make 0.0 0.0 1.0\n") },
+				scale:  timeScale{tick: time.Millisecond},
+			},
+			wantErr: ErrInvalidProcesses,
+		},
+		{
+			name: "perf without tick",
+			args: args{
+				format: "perf",
+				r:      func(t *testing.T) io.Reader { return openTrace(t, "perf_timehist.txt") },
+			},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := inputFormats[tt.args.format](tt.args.r(t), tt.args.scale)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inputFormats[%q]() = %v, want %v", tt.args.format, got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvariant is returned when a schedule breaks one of the scheduling invariants.
+var ErrInvariant = errors.New("schedule invariant violated")
+
+// verifySchedule checks a finished schedule of processes against the invariants every policy must keep:
+// • TimeSlices on the same CPU never overlap, idle or not, and neither do the slices of a process
+// • no process runs before it arrives and is admitted into memory
+// • no process runs before the processes it depends on have exited
+// • the memory of the processes admitted at any time never exceeds the memory capacity
+// • the work each process does, at the speed of each slice's frequency state, adds up to its burst duration
+// • each process's wait is its turnaround less the time it runs and is blocked on I/O, and its turnaround ends
+// when its last slice stops
+// • a process never runs while it's blocked on I/O
+// • work-conserving (single CPU) policies never leave the CPU idle while a process is ready, rather than
+// blocked on I/O
+func verifySchedule(processes []Process, res Result, workConserving bool) error {
+	byPID := make(map[int64]Process, len(processes))
+	for _, p := range processes {
+		if _, ok := byPID[p.ProcessID]; ok {
+			return fmt.Errorf("%w: duplicate process ID %d", ErrInvariant, p.ProcessID)
+		}
+		byPID[p.ProcessID] = p
+	}
+
+	if len(res.Stats) != len(processes) {
+		return fmt.Errorf("%w: %d processes scheduled, want %d", ErrInvariant, len(res.Stats), len(processes))
+	}
+	// Each process is ready once it's admitted, and a process admitted at once is ready when it arrives.
+	ready := make(map[int64]int64, len(processes))
+	for _, s := range res.Stats {
+		if _, ok := byPID[s.ProcessID]; !ok {
+			return fmt.Errorf("%w: timing reported for unknown process %d", ErrInvariant, s.ProcessID)
+		}
+		if s.Admission < 0 {
+			return fmt.Errorf("%w: process %d is admitted %d before it arrives", ErrInvariant, s.ProcessID, -s.Admission)
+		}
+		ready[s.ProcessID] = s.ArrivalTime + s.Admission
+	}
+
+	gantt := make([]TimeSlice, len(res.Gantt))
+	copy(gantt, res.Gantt)
+	sort.SliceStable(gantt, func(i, j int) bool { return gantt[i].Start < gantt[j].Start })
+
+	var (
+		ran     = make(map[int64]int64, len(processes))
+		work    = make(map[int64]fullSpeed, len(processes))
+		last    = make(map[int64]TimeSlice, len(processes))
+		started = make(map[int64]int64, len(processes))
+		exit    = make(map[int64]int64, len(processes))
+		cpuBusy = make(map[int]TimeSlice)
+		pidBusy = make(map[int64]TimeSlice)
+	)
+	for _, slice := range gantt {
+		// Idle slices only have to fit in the gaps between the slices on their CPU
+		if slice.Idle {
+			if slice.Stop < slice.Start || slice.CPU < 0 {
+				return fmt.Errorf("%w: idle slice %d-%d on CPU %d is invalid", ErrInvariant, slice.Start, slice.Stop, slice.CPU)
+			}
+			if busy := cpuBusy[slice.CPU]; busy != (TimeSlice{}) && slice.Start < busy.Stop {
+				return fmt.Errorf("%w: idle slice %d-%d on CPU %d overlaps slice %d-%d of process %d", ErrInvariant,
+					slice.Start, slice.Stop, slice.CPU, busy.Start, busy.Stop, busy.PID)
+			}
+			if slice.Stop >= cpuBusy[slice.CPU].Stop {
+				cpuBusy[slice.CPU] = slice
+			}
+			continue
+		}
+		p, ok := byPID[slice.PID]
+		if !ok {
+			return fmt.Errorf("%w: slice %d-%d runs unknown process %d", ErrInvariant, slice.Start, slice.Stop, slice.PID)
+		}
+		if slice.Stop < slice.Start {
+			return fmt.Errorf("%w: slice %d-%d of process %d stops before it starts", ErrInvariant, slice.Start, slice.Stop, slice.PID)
+		}
+		if slice.Level < 0 || slice.Level > 0 && slice.Level >= len(res.CPU.states) {
+			return fmt.Errorf("%w: slice %d-%d of process %d runs at unknown frequency state %d", ErrInvariant,
+				slice.Start, slice.Stop, slice.PID, slice.Level)
+		}
+		if slice.CPU < 0 {
+			return fmt.Errorf("%w: slice %d-%d of process %d runs on CPU %d", ErrInvariant, slice.Start, slice.Stop, slice.PID, slice.CPU)
+		}
+		if slice.Start < p.ArrivalTime {
+			return fmt.Errorf("%w: process %d runs at %d before it arrives at %d", ErrInvariant, slice.PID, slice.Start, p.ArrivalTime)
+		}
+		if slice.Start < ready[slice.PID] {
+			return fmt.Errorf("%w: process %d runs at %d before it is admitted at %d", ErrInvariant, slice.PID, slice.Start, ready[slice.PID])
+		}
+		// Slices are in start order, so each only has to be checked against the latest stopping earlier slice
+		// on its CPU, and of its process on any CPU.
+		for _, busy := range []TimeSlice{cpuBusy[slice.CPU], pidBusy[slice.PID]} {
+			if busy != (TimeSlice{}) && slice.Start < busy.Stop {
+				return fmt.Errorf("%w: slice %d-%d of process %d on CPU %d overlaps slice %d-%d of process %d on CPU %d",
+					ErrInvariant, slice.Start, slice.Stop, slice.PID, slice.CPU, busy.Start, busy.Stop, busy.PID, busy.CPU)
+			}
+		}
+		if slice.Stop >= cpuBusy[slice.CPU].Stop {
+			cpuBusy[slice.CPU] = slice
+		}
+		if slice.Stop >= pidBusy[slice.PID].Stop {
+			pidBusy[slice.PID] = slice
+		}
+		if _, ok := started[slice.PID]; !ok {
+			started[slice.PID] = slice.Start
+		}
+		ran[slice.PID] += slice.Stop - slice.Start
+		work[slice.PID] = work[slice.PID].add(res.CPU.fullSpeed(slice))
+		last[slice.PID] = slice
+		exit[slice.PID] = maxi(exit[slice.PID], slice.Stop)
+	}
+
+	for _, s := range res.Stats {
+		// Only a process's last slice may run for part of a tick longer than it needs
+		if w := work[s.ProcessID]; w.ticks != s.BurstDuration || w.hundredths >= res.CPU.speed(last[s.ProcessID].Level) {
+			return fmt.Errorf("%w: process %d runs for %d.%02d at full speed, want its burst of %d", ErrInvariant,
+				s.ProcessID, w.ticks, w.hundredths, s.BurstDuration)
+		}
+		if s.Exit != exit[s.ProcessID] {
+			return fmt.Errorf("%w: process %d exits at %d, but its last slice stops at %d", ErrInvariant,
+				s.ProcessID, s.Exit, exit[s.ProcessID])
+		}
+		if s.Turnaround != s.Exit-s.ArrivalTime {
+			return fmt.Errorf("%w: process %d has turnaround %d, want %d", ErrInvariant,
+				s.ProcessID, s.Turnaround, s.Exit-s.ArrivalTime)
+		}
+		if s.Wait != s.Turnaround-ran[s.ProcessID]-s.IO {
+			return fmt.Errorf("%w: process %d has wait %d, want %d", ErrInvariant,
+				s.ProcessID, s.Wait, s.Turnaround-ran[s.ProcessID]-s.IO)
+		}
+		for _, dep := range s.DependsOn {
+			if started[s.ProcessID] < exit[dep] {
+				return fmt.Errorf("%w: process %d runs at %d before process %d it depends on exits at %d", ErrInvariant,
+					s.ProcessID, started[s.ProcessID], dep, exit[dep])
+			}
+		}
+	}
+
+	blocked, err := verifyIO(res, gantt, ready, exit)
+	if err != nil {
+		return err
+	}
+	if err := verifyMemory(res); err != nil {
+		return err
+	}
+	if !workConserving {
+		return nil
+	}
+
+	// Find the gaps where the CPU is idle, in order
+	type gap struct {
+		from, to int64
+	}
+	var (
+		gaps     []gap
+		idleFrom int64
+	)
+	for _, slice := range gantt {
+		if slice.Idle {
+			continue
+		}
+		if slice.Start > idleFrom {
+			gaps = append(gaps, gap{from: idleFrom, to: slice.Start})
+		}
+		idleFrom = maxi(idleFrom, slice.Stop)
+	}
+
+	// A process is ready at some point in a gap if it's admitted before the gap ends and finishes after the gap
+	// starts. If the first gap to end after it's admitted starts after it finishes, so do the later gaps.
+	// A process that blocks on I/O may leave gaps idle while it's blocked, so it's checked against each gap.
+	for _, p := range processes {
+		g := sort.Search(len(gaps), func(g int) bool { return gaps[g].to > ready[p.ProcessID] })
+		for ; g < len(gaps) && gaps[g].from < exit[p.ProcessID]; g++ {
+			from, to := maxi(ready[p.ProcessID], gaps[g].from), mini(exit[p.ProcessID], gaps[g].to)
+			for _, io := range blocked[p.ProcessID] {
+				if io.Start <= from && io.Stop > from {
+					from = io.Stop
+				}
+			}
+			if from < to {
+				return fmt.Errorf("%w: CPU is idle from %d to %d while process %d is ready", ErrInvariant,
+					gaps[g].from, gaps[g].to, p.ProcessID)
+			}
+			if len(blocked[p.ProcessID]) == 0 {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyFirstCome checks that a first-come, first-serve schedule starts no process before a process that was
+// ready before it, once it arrived and was admitted.
+func verifyFirstCome(res Result) error {
+	started := make(map[int64]int64, len(res.Stats))
+	for _, slice := range res.Gantt {
+		if start, ok := started[slice.PID]; !slice.Idle && (!ok || slice.Start < start) {
+			started[slice.PID] = slice.Start
+		}
+	}
+	stats := append([]ProcessStats(nil), res.Stats...)
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i].ArrivalTime+stats[i].Admission, stats[j].ArrivalTime+stats[j].Admission
+		if a != b {
+			return a < b
+		}
+		return started[stats[i].ProcessID] < started[stats[j].ProcessID]
+	})
+	for i := 1; i < len(stats); i++ {
+		before, s := stats[i-1], stats[i]
+		if started[s.ProcessID] < started[before.ProcessID] {
+			return fmt.Errorf("%w: process %d ready at %d runs at %d, before process %d ready at %d", ErrInvariant,
+				s.ProcessID, s.ArrivalTime+s.Admission, started[s.ProcessID],
+				before.ProcessID, before.ArrivalTime+before.Admission)
+		}
+	}
+
+	return nil
+}
+
+// verifyIO checks that each process is only blocked on I/O while it's not running, between when it's ready and
+// when it exits, for as long as it reports, given the slices in start order. It returns when each process was
+// blocked, in order.
+func verifyIO(res Result, gantt []TimeSlice, ready, exit map[int64]int64) (map[int64][]TimeSlice, error) {
+	blocked := make(map[int64][]TimeSlice)
+	for _, io := range res.IO {
+		blocked[io.PID] = append(blocked[io.PID], io)
+	}
+	for _, s := range res.Stats {
+		var total int64
+		for _, io := range blocked[s.ProcessID] {
+			total += io.Stop - io.Start
+		}
+		if total != s.IO {
+			return nil, fmt.Errorf("%w: process %d is blocked on I/O for %d, but reports %d", ErrInvariant,
+				s.ProcessID, total, s.IO)
+		}
+	}
+	if len(blocked) == 0 {
+		return blocked, nil
+	}
+
+	busy := make(map[int64][]TimeSlice, len(blocked))
+	for pid, ios := range blocked {
+		if _, ok := ready[pid]; !ok {
+			return nil, fmt.Errorf("%w: unknown process %d is blocked on I/O", ErrInvariant, pid)
+		}
+		sort.Slice(ios, func(i, j int) bool { return ios[i].Start < ios[j].Start })
+		busy[pid] = append(busy[pid], ios...)
+		for _, io := range ios {
+			if io.Start < ready[pid] || io.Stop > exit[pid] || io.Stop <= io.Start {
+				return nil, fmt.Errorf("%w: process %d is blocked on I/O from %d to %d, outside %d to %d", ErrInvariant,
+					pid, io.Start, io.Stop, ready[pid], exit[pid])
+			}
+		}
+	}
+	for _, slice := range gantt {
+		if _, ok := busy[slice.PID]; ok && !slice.Idle {
+			busy[slice.PID] = append(busy[slice.PID], slice)
+		}
+	}
+	for pid, slices := range busy {
+		sort.SliceStable(slices, func(i, j int) bool { return slices[i].Start < slices[j].Start })
+		for i := 1; i < len(slices); i++ {
+			if slices[i].Start < slices[i-1].Stop {
+				return nil, fmt.Errorf("%w: process %d runs at %d while it's blocked on I/O, or is blocked twice", ErrInvariant,
+					pid, slices[i].Start)
+			}
+		}
+	}
+
+	return blocked, nil
+}
+
+// verifyMemory checks that the memory of the processes admitted at any time never exceeds the memory capacity.
+func verifyMemory(res Result) error {
+	if res.MemoryCapacity == 0 {
+		return nil
+	}
+
+	// Each process holds its memory from when it's admitted until it exits
+	type change struct {
+		at, memory int64
+	}
+	changes := make([]change, 0, 2*len(res.Stats))
+	for _, s := range res.Stats {
+		changes = append(changes,
+			change{at: s.ArrivalTime + s.Admission, memory: s.Memory},
+			change{at: s.Exit, memory: -s.Memory})
+	}
+	// Memory freed at the same time as it's allocated can be reused
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].at != changes[j].at {
+			return changes[i].at < changes[j].at
+		}
+		return changes[i].memory < changes[j].memory
+	})
+	var used int64
+	for _, c := range changes {
+		if used += c.memory; used > res.MemoryCapacity {
+			return fmt.Errorf("%w: %d memory in use at %d, more than the capacity of %d", ErrInvariant,
+				used, c.at, res.MemoryCapacity)
+		}
+	}
+
+	return nil
+}
+
+// maxi returns the maximum of two integers
+func maxi(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,431 @@
+package scheduler
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// workload is a random set of processes for property-based tests:
+// unique but shuffled process IDs, arrivals in any order, positive bursts, some threads grouped into jobs,
+// memory needs that fit in randomParams, and some processes depending on earlier ones.
+type workload []Process
+
+func (workload) Generate(r *rand.Rand, size int) reflect.Value {
+	ids := r.Perm(1 + r.Intn(size))
+	processes := make([]Process, len(ids))
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(ids[i]) + 1,
+			ArrivalTime:   r.Int63n(int64(2*size) + 1),
+			BurstDuration: 1 + r.Int63n(10),
+			Priority:      1 + r.Int63n(50),
+			JobID:         r.Int63n(4),
+			Memory:        r.Int63n(randomParams[1].memory.capacity + 1),
+		}
+		for deps := r.Intn(3); i > 0 && deps > 0 && r.Intn(2) == 0; deps-- {
+			processes[i].DependsOn = append(processes[i].DependsOn, processes[r.Intn(i)].ProcessID)
+		}
+	}
+
+	return reflect.ValueOf(workload(processes))
+}
+
+// randomParams are the policy parameters random workloads are scheduled with.
+var randomParams = []Params{
+	defaultParams,
+	{timeQuantum: 3, weights: 2, cpus: 3, memory: memoryConfig{capacity: 16, fit: "first"}, cpu: cpuModel{
+		states: []pstate{{speed: 100, power: 9}, {speed: 66, power: 4}, {speed: 33, power: 1}},
+	}},
+	{timeQuantum: 1, weights: 1, cpus: 1, memory: memoryConfig{capacity: 16, fit: "best"}, cpu: cpuModel{
+		states: []pstate{{speed: 100, power: 8}, {speed: 7, power: 1}},
+	}},
+	{timeQuantum: 4, weights: 3, cpus: 2, arrivalsFirst: true, io: ioModel{every: 3, time: 5}, cpu: defaultParams.cpu},
+}
+
+func Test_verifySchedule_policies(t *testing.T) {
+	t.Parallel()
+	for _, pol := range policies {
+		pol := pol
+		t.Run(pol.name, func(t *testing.T) {
+			t.Parallel()
+			check := func(w workload) bool {
+				for _, p := range randomParams {
+					if err := pol.Verify(w, pol.schedule(w, p)); err != nil {
+						t.Logf("%v with %+v: %v", []Process(w), p, err)
+						return false
+					}
+				}
+				return true
+			}
+			if err := quick.Check(check, &quick.Config{MaxCount: 500}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_verifySchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+	}
+	type args struct {
+		processes      []Process
+		res            Result
+		workConserving bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "valid",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 2, Turnaround: 4, Exit: 5},
+					},
+				},
+				workConserving: true,
+			},
+		},
+		{
+			name: "duplicate process ID",
+			args: args{
+				processes: []Process{processes[0], processes[0]},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "overlapping slices",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 2, Stop: 4}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 1, Turnaround: 3, Exit: 4},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "idle slice overlaps a process",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {Start: 2, Stop: 3, Idle: true}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 2, Turnaround: 4, Exit: 5},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "idle while ready",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {Start: 3, Stop: 4, Idle: true}, {PID: 2, Start: 4, Stop: 6}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 3, Turnaround: 5, Exit: 6},
+					},
+				},
+				workConserving: true,
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "blocked on I/O",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 1, Start: 4, Stop: 6}},
+					IO:    []TimeSlice{{PID: 1, Start: 1, Stop: 4}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, IO: 3, Turnaround: 6, Exit: 6},
+						{Process: processes[1], Wait: 0, Turnaround: 2, Exit: 3},
+					},
+				},
+				workConserving: true,
+			},
+		},
+		{
+			name: "runs while blocked on I/O",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 6}},
+					IO:    []TimeSlice{{PID: 1, Start: 1, Stop: 3}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: -1, IO: 2, Turnaround: 4, Exit: 4},
+						{Process: processes[1], Wait: 3, Turnaround: 5, Exit: 6},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "reports the wrong I/O",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 1, Start: 4, Stop: 6}},
+					IO:    []TimeSlice{{PID: 1, Start: 1, Stop: 4}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 1, IO: 2, Turnaround: 6, Exit: 6},
+						{Process: processes[1], Wait: 0, Turnaround: 2, Exit: 3},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "idle while ready after I/O",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 3}, {PID: 1, Start: 5, Stop: 7}},
+					IO:    []TimeSlice{{PID: 1, Start: 1, Stop: 4}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 1, IO: 3, Turnaround: 7, Exit: 7},
+						{Process: processes[1], Wait: 0, Turnaround: 2, Exit: 3},
+					},
+				},
+				workConserving: true,
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "runs before arrival",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 2, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 2, Turnaround: 5, Exit: 5},
+						{Process: processes[1], Wait: -1, Turnaround: 1, Exit: 2},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "slices don't add up to burst",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: -1, Turnaround: 2, Exit: 2},
+						{Process: processes[1], Wait: 1, Turnaround: 3, Exit: 4},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "wrong wait",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 4, Turnaround: 4, Exit: 5},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "idle with a ready process",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 4, Stop: 6}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 3, Turnaround: 5, Exit: 6},
+					},
+				},
+				workConserving: true,
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "runs before admission",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Admission: 3, Wait: 2, Turnaround: 4, Exit: 5},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "runs before a dependency exits",
+			args: args{
+				processes: []Process{processes[0], {ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, DependsOn: []int64{1}}},
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 1, Stop: 3, CPU: 1}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: Process{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, DependsOn: []int64{1}},
+							Wait: 0, Turnaround: 2, Exit: 3},
+					},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "too short at a lower frequency",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5, Level: 1}, {PID: 2, Start: 5, Stop: 7}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 5, Exit: 5},
+						{Process: processes[1], Wait: 4, Turnaround: 6, Exit: 7},
+					},
+					CPU: cpuModel{states: []pstate{{speed: 100, power: 8}, {speed: 50, power: 2}}},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "memory over capacity",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: Process{ProcessID: 1, BurstDuration: 3, Memory: 6}, Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: Process{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Memory: 6}, Wait: 2, Turnaround: 4, Exit: 5},
+					},
+					MemoryCapacity: 10,
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name: "memory reused when freed",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+					Stats: []ProcessStats{
+						{Process: Process{ProcessID: 1, BurstDuration: 3, Memory: 6}, Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: Process{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Memory: 6}, Admission: 2, Wait: 2, Turnaround: 4, Exit: 5},
+					},
+					MemoryCapacity: 10,
+				},
+			},
+		},
+		{
+			name: "idle allowed when not work-conserving",
+			args: args{
+				processes: processes,
+				res: Result{
+					Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 4, Stop: 6}},
+					Stats: []ProcessStats{
+						{Process: processes[0], Wait: 0, Turnaround: 3, Exit: 3},
+						{Process: processes[1], Wait: 3, Turnaround: 5, Exit: 6},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := verifySchedule(tt.args.processes, tt.args.res, tt.args.workConserving)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifySchedule() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicy_Verify(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 5},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 1, ArrivalTime: 3, BurstDuration: 2},
+	}
+	tests := []struct {
+		name    string
+		policy  string
+		res     Result
+		wantErr error
+	}{
+		{
+			name:   "first come",
+			policy: "fcfs",
+			res: Result{
+				Gantt: []TimeSlice{{PID: 3, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 7}, {PID: 1, Start: 7, Stop: 9}},
+				Stats: []ProcessStats{
+					{Process: processes[0], Wait: 0, Turnaround: 5, Exit: 5},
+					{Process: processes[1], Wait: 4, Turnaround: 6, Exit: 7},
+					{Process: processes[2], Wait: 4, Turnaround: 6, Exit: 9},
+				},
+			},
+		},
+		{
+			name:   "later arrival first",
+			policy: "fcfs",
+			res: Result{
+				Gantt: []TimeSlice{{PID: 3, Start: 0, Stop: 5}, {PID: 1, Start: 5, Stop: 7}, {PID: 2, Start: 7, Stop: 9}},
+				Stats: []ProcessStats{
+					{Process: processes[0], Wait: 0, Turnaround: 5, Exit: 5},
+					{Process: processes[1], Wait: 6, Turnaround: 8, Exit: 9},
+					{Process: processes[2], Wait: 2, Turnaround: 4, Exit: 7},
+				},
+			},
+			wantErr: ErrInvariant,
+		},
+		{
+			name:   "later arrival first without first come",
+			policy: "sjf",
+			res: Result{
+				Gantt: []TimeSlice{{PID: 3, Start: 0, Stop: 5}, {PID: 1, Start: 5, Stop: 7}, {PID: 2, Start: 7, Stop: 9}},
+				Stats: []ProcessStats{
+					{Process: processes[0], Wait: 0, Turnaround: 5, Exit: 5},
+					{Process: processes[1], Wait: 6, Turnaround: 8, Exit: 9},
+					{Process: processes[2], Wait: 2, Turnaround: 4, Exit: 7},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pol, err := LookupPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pol.Verify(processes, tt.res); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanditjindal/CSCE4600/Project1/scheduler"
+)
+
+// web is the page served at the root, which runs schedules through the API and charts them.
+//
+//go:embed web
+var web embed.FS
+
+const (
+	// maxRequestSize bounds the body of a schedule request.
+	maxRequestSize = 1 << 20
+	// maxProcesses bounds the processes a request schedules.
+	maxProcesses = 10_000
+	// maxSimulatedTime bounds the time a request's processes can take to run one after another from the last
+	// arrival, in ticks, so every policy finishes scheduling them within scheduleTimeout.
+	maxSimulatedTime = 1_000_000
+	// scheduleTimeout bounds the time a schedule request takes to answer.
+	scheduleTimeout = 30 * time.Second
+)
+
+type (
+	// scheduleRequest is the JSON body of a schedule request.
+	scheduleRequest struct {
+		// Format is the input format of Processes: csv (the default), json, procstat or perf.
+		Format    string `json:"format"`
+		Processes string `json:"processes"`
+		// Policies names the policies to run, or every policy if it's empty.
+		Policies []string `json:"policies"`
+		// Params sets parameters by the names of their flags, e.g. {"quantum": 3, "unit": "ms"}.
+		Params map[string]json.RawMessage `json:"params"`
+		// Verify checks each schedule against the scheduling invariants.
+		Verify bool `json:"verify"`
+	}
+	// policyResult is the schedule of the processes by a policy, and its report as the CLI renders it.
+	policyResult struct {
+		Policy   string           `json:"policy"`
+		Title    string           `json:"title"`
+		Schedule scheduler.Result `json:"schedule"`
+		Report   string           `json:"report"`
+		// Invariant is the first scheduling invariant the schedule breaks, when it's verified.
+		Invariant string `json:"invariant,omitempty"`
+	}
+)
+
+// serve runs the HTTP API and web page until the server fails.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to serve the API and web page on")
+	_ = flags.Parse(args)
+
+	log.Printf("serving on http://%s", *addr)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       scheduleTimeout,
+	}
+	return server.ListenAndServe()
+}
+
+// newServer returns the handler of the web page and API:
+// • GET / serves the web page
+// • GET /api/policies lists the names and titles of the policies
+// • POST /api/schedule schedules processes, returning a policyResult for each policy
+func newServer() http.Handler {
+	mux := http.NewServeMux()
+	page, err := fs.Sub(web, "web")
+	if err != nil {
+		panic(err)
+	}
+	mux.Handle("/", http.FileServer(http.FS(page)))
+	mux.HandleFunc("/api/policies", handlePolicies)
+	mux.Handle("/api/schedule", http.TimeoutHandler(http.HandlerFunc(handleSchedule), scheduleTimeout,
+		`{"error": "scheduling took too long"}`))
+
+	return mux
+}
+
+func handlePolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+	type policyName struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+	names := make([]policyName, 0)
+	for _, pol := range scheduler.Policies() {
+		names = append(names, policyName{Name: pol.Name(), Title: pol.Title()})
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+
+// handleSchedule schedules the processes of a request, which is either a scheduleRequest in a JSON body, or a
+// scheduling file in the body with the format, policies, verify and parameters in the query string, e.g.
+//
+//	curl --data-binary @processes.csv 'localhost:8080/api/schedule?policy=rr&policy=sjf&quantum=3'
+func handleSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+	req, err := readScheduleRequest(http.MaxBytesReader(w, r.Body, maxRequestSize), r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	results, err := runSchedules(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func readScheduleRequest(body io.Reader, r *http.Request) (scheduleRequest, error) {
+	var req scheduleRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return req, fmt.Errorf("%w: %v", scheduler.ErrInvalidArgs, err)
+		}
+		return req, nil
+	}
+
+	processes, err := io.ReadAll(body)
+	if err != nil {
+		return req, err
+	}
+	req.Processes = string(processes)
+	req.Params = make(map[string]json.RawMessage)
+	for name, values := range r.URL.Query() {
+		switch name {
+		case "format":
+			req.Format = values[len(values)-1]
+		case "policy":
+			req.Policies = append(req.Policies, values...)
+		case "verify":
+			if req.Verify, err = strconv.ParseBool(values[len(values)-1]); err != nil {
+				return req, fmt.Errorf("%w: verify: %v", scheduler.ErrInvalidArgs, err)
+			}
+		default:
+			value, _ := json.Marshal(values[len(values)-1])
+			req.Params[name] = value
+		}
+	}
+
+	return req, nil
+}
+
+// runSchedules loads the processes of a request and runs them through each of its policies.
+func runSchedules(req scheduleRequest) ([]policyResult, error) {
+	p := scheduler.DefaultParams()
+	names := make([]string, 0, len(req.Params))
+	for name := range req.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Requests mustn't run programs on the server
+		if name == "plugin" {
+			return nil, fmt.Errorf("%w: plugins can't be run over the API", scheduler.ErrInvalidArgs)
+		}
+		// Parameters may be given as JSON strings or as numbers
+		value := string(req.Params[name])
+		var s string
+		if err := json.Unmarshal(req.Params[name], &s); err == nil {
+			value = s
+		}
+		if err := p.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if req.Format == "" {
+		req.Format = "csv"
+	}
+	processes, err := scheduler.Load(strings.NewReader(req.Processes), req.Format, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSimulatedLength(processes); err != nil {
+		return nil, err
+	}
+
+	policies := scheduler.Policies()
+	if len(req.Policies) > 0 {
+		policies = policies[:0]
+		for _, name := range req.Policies {
+			pol, err := scheduler.LookupPolicy(name)
+			if err != nil {
+				return nil, err
+			}
+			policies = append(policies, pol)
+		}
+	}
+
+	results := make([]policyResult, 0, len(policies))
+	for _, pol := range policies {
+		res, err := pol.Schedule(processes, p)
+		if err != nil {
+			return nil, err
+		}
+		var report bytes.Buffer
+		res.Render(&report, pol.Title())
+		result := policyResult{Policy: pol.Name(), Title: pol.Title(), Schedule: res, Report: report.String()}
+		if req.Verify {
+			if err := pol.Verify(processes, res); err != nil {
+				result.Invariant = err.Error()
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// checkSimulatedLength returns an error if there are too many processes to schedule over the API, or they'd
+// take too long to run.
+func checkSimulatedLength(processes []scheduler.Process) error {
+	if len(processes) > maxProcesses {
+		return fmt.Errorf("%w: %d processes, at most %d can be scheduled", scheduler.ErrInvalidArgs, len(processes), maxProcesses)
+	}
+	var last, total int64
+	for _, proc := range processes {
+		if proc.ArrivalTime > last {
+			last = proc.ArrivalTime
+		}
+		if last > maxSimulatedTime || proc.BurstDuration > maxSimulatedTime-last-total {
+			return fmt.Errorf("%w: processes can run past %d ticks", scheduler.ErrInvalidArgs, maxSimulatedTime)
+		}
+		total += proc.BurstDuration
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_handleSchedule(t *testing.T) {
+	t.Parallel()
+	const processes = "1,5,0,2\n2,9,3,1\n3,6,6,3\n"
+	var tooMany strings.Builder
+	for i := 1; i <= maxProcesses+1; i++ {
+		fmt.Fprintf(&tooMany, "%d,1,0,1\n", i)
+	}
+
+	type args struct {
+		target      string
+		contentType string
+		body        string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+		wantTitles []string
+		wantExits  []string
+	}{
+		{
+			name: "json request",
+			args: args{
+				target:      "/api/schedule",
+				contentType: "application/json",
+				body:        `{"processes": "1,5,0,2\n2,9,3,1\n3,6,6,3\n", "policies": ["rr"], "params": {"quantum": 3}}`,
+			},
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Round-robin"},
+			wantExits:  []string{"5", "20", "17"},
+		},
+		{
+			name: "file with query parameters",
+			args: args{
+				target:      "/api/schedule?policy=sjf&policy=rr&quantum=3&verify=true",
+				contentType: "text/csv",
+				body:        processes,
+			},
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Shortest-job-first (preemptive)", "Round-robin"},
+		},
+		{
+			name:       "every policy",
+			args:       args{target: "/api/schedule", contentType: "text/csv", body: processes},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "unknown policy",
+			args: args{
+				target:      "/api/schedule",
+				contentType: "application/json",
+				body:        `{"processes": "1,5,0,2\n", "policies": ["lottery"]}`,
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown parameter",
+			args:       args{target: "/api/schedule?slice=2", contentType: "text/csv", body: processes},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "plugin",
+			args:       args{target: "/api/schedule?policy=plugin&plugin=sh", contentType: "text/csv", body: processes},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "chart too wide",
+			args:       args{target: "/api/schedule?width=20000000", contentType: "text/csv", body: processes},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid processes",
+			args:       args{target: "/api/schedule", contentType: "text/csv", body: "1,-5,0,2\n"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many processes",
+			args:       args{target: "/api/schedule", contentType: "text/csv", body: tooMany.String()},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "runs too long",
+			args:       args{target: "/api/schedule", contentType: "text/csv", body: "1,600000,0,1\n2,600000,0,1\n"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "arrives too late",
+			args:       args{target: "/api/schedule", contentType: "text/csv", body: "1,1,1000000,1\n"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "body too large",
+			args: args{
+				target:      "/api/schedule",
+				contentType: "text/csv",
+				body:        strings.Repeat("1,5,0,2\n", maxRequestSize/8+1),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed json",
+			args:       args{target: "/api/schedule", contentType: "application/json", body: "{"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, tt.args.target, strings.NewReader(tt.args.body))
+			req.Header.Set("Content-Type", tt.args.contentType)
+			rec := httptest.NewRecorder()
+			newServer().ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				var body struct{ Error string }
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+					t.Errorf("error body = %s, want an error message", rec.Body)
+				}
+				return
+			}
+
+			var results []struct {
+				Title     string
+				Report    string
+				Invariant string
+				Schedule  struct {
+					Processes []struct{ Exit json.Number }
+				}
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+				t.Fatal(err)
+			}
+			if len(results) == 0 {
+				t.Fatal("no results")
+			}
+			for i, want := range tt.wantTitles {
+				if results[i].Title != want {
+					t.Errorf("results[%d].Title = %q, want %q", i, results[i].Title, want)
+				}
+			}
+			for i, want := range tt.wantExits {
+				if got := results[0].Schedule.Processes[i].Exit.String(); got != want {
+					t.Errorf("process %d exits at %s, want %s", i, got, want)
+				}
+			}
+			for _, res := range results {
+				if !strings.Contains(res.Report, res.Title) {
+					t.Errorf("report of %s doesn't have its title", res.Title)
+				}
+			}
+		})
+	}
+}
+
+func Test_newServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		want       string
+	}{
+		{name: "web page", method: http.MethodGet, target: "/", wantStatus: http.StatusOK, want: "<title>CPU Scheduler</title>"},
+		{name: "policies", method: http.MethodGet, target: "/api/policies", wantStatus: http.StatusOK, want: `"name":"rr"`},
+		{name: "schedule by GET", method: http.MethodGet, target: "/api/schedule", wantStatus: http.StatusMethodNotAllowed},
+		{name: "policies by POST", method: http.MethodPost, target: "/api/policies", wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			newServer().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body, tt.want)
+			}
+		})
+	}
+}